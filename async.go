@@ -0,0 +1,201 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// AsyncWriter decouples logging from I/O by queueing log lines in a channel. A background goroutine drains the queue
+// and writes the lines to the wrapped Writer. When the queue is full, Write blocks until the background goroutine has
+// made room for the next line. As such, no logs are dropped, at the expense of (temporarily) blocking the caller when
+// the destination cannot keep up.
+type AsyncWriter struct {
+	writer Writer
+	queue  chan asyncItem
+	done   chan struct{}
+	mu     sync.Mutex   // protects the wrapped writer
+	state  sync.RWMutex // protects the queue from being closed while writing
+	closed bool
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// asyncItem defines an item in the queue of an AsyncWriter. An item contains either a log line, or a channel to signal
+// that all preceding lines have been written. Lines written by Bypass are marked as such, while lines written by
+// WriteLevel keep their level.
+type asyncItem struct {
+	line    []byte
+	level   zerolog.Level
+	bypass  bool
+	flushed chan struct{}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// drain writes all queued log lines to the wrapped writer until the queue is closed.
+func (w *AsyncWriter) drain() {
+	defer close(w.done)
+	for item := range w.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		w.mu.Lock()
+		if item.bypass {
+			bypass(w.writer, item.line) //nolint:errcheck // errors cannot be reported back to the caller
+		} else {
+			writeLevel(w.writer, item.level, item.line) //nolint:errcheck // errors cannot be reported back to the caller
+		}
+		w.mu.Unlock()
+	}
+}
+
+// enqueue queues a copy of p together with its level, marking it as written by Bypass if needed. It returns an error
+// if the writer has been closed.
+func (w *AsyncWriter) enqueue(p []byte, level zerolog.Level, bypass bool) (n int, err error) {
+	w.state.RLock()
+	defer w.state.RUnlock()
+	if w.closed {
+		return 0, errors.New("Cannot write to closed async writer")
+	}
+
+	// copy the input, as the caller may reuse the underlying buffer
+	line := make([]byte, len(p))
+	copy(line, p)
+	w.queue <- asyncItem{line: line, level: level, bypass: bypass}
+
+	return len(p), nil
+}
+
+// writeBypass queues the log to be written to the wrapped writer in Default format, if supported.
+func (w *AsyncWriter) writeBypass(p []byte) (n int, err error) {
+	return w.enqueue(p, zerolog.NoLevel, true)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewAsyncWriter creates a writer that queues up to queueSize log lines and writes them to w on a background
+// goroutine. A queueSize of zero or less results in an unbuffered queue. Call Close to flush the queue and to stop the
+// background goroutine.
+func NewAsyncWriter(w Writer, queueSize int) *AsyncWriter {
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	a := AsyncWriter{
+		writer: w,
+		queue:  make(chan asyncItem, queueSize),
+		done:   make(chan struct{}),
+	}
+	go a.drain()
+
+	return &a
+}
+
+// Close writes all queued log lines to the wrapped writer and stops the background goroutine. Subsequent writes return
+// an error. Calling Close more than once has no effect.
+func (w *AsyncWriter) Close() error {
+	w.state.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.state.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// Flush blocks until all log lines queued before the call to Flush have been written to the wrapped writer. Unlike
+// Close, the background goroutine keeps running. Flush returns immediately if the writer has been closed, as Close
+// already writes all queued lines.
+func (w *AsyncWriter) Flush() error {
+	return w.FlushContext(context.Background())
+}
+
+// FlushContext blocks until all log lines queued before the call have been written to the wrapped writer, similar to
+// Flush, or until the context is done. In the latter case, FlushContext returns the error of the context and abandons
+// the wait. The background goroutine continues writing the queued lines, but lines still queued when the program exits
+// are lost.
+func (w *AsyncWriter) FlushContext(ctx context.Context) error {
+	w.state.RLock()
+	if w.closed {
+		w.state.RUnlock()
+		return nil
+	}
+	flushed := make(chan struct{})
+	select {
+	case w.queue <- asyncItem{flushed: flushed}:
+		w.state.RUnlock()
+	case <-ctx.Done():
+		w.state.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetFormatting updates the log format and color coding of the wrapped writer. It is safe to call SetFormatting while
+// the background goroutine is writing.
+func (w *AsyncWriter) SetFormatting(format Format, noColor bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writer.SetFormatting(format, noColor)
+}
+
+// Write implements the io.Writer interface for AsyncWriter. It queues a copy of p and returns immediately, unless the
+// queue is full. Write returns an error if the writer has been closed.
+func (w *AsyncWriter) Write(p []byte) (n int, err error) {
+	return w.enqueue(p, zerolog.NoLevel, false)
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for AsyncWriter. It queues a copy of p together with its
+// level, which is passed to the wrapped writer if supported.
+func (w *AsyncWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	return w.enqueue(p, level, false)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,155 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestAsyncWriter(t *testing.T) {
+	const count = 1000
+
+	// log a burst of messages using a small queue
+	b := NewBufferedWriter(JSON, true)
+	w := NewAsyncWriter(b, 10)
+	l := NewLogger(JSON, true, w)
+	for i := 0; i < count; i++ {
+		l.Infof("message %d", i)
+	}
+	w.SetFormatting(JSON, true)
+
+	// test no lines are lost after closing the writer
+	require.Nil(t, w.Close())
+	got := b.Buffer()
+	require.Len(t, got, count)
+	m, e := UnmarshalLog([]byte(got[count-1]))
+	require.Nil(t, e)
+	assert.Equal(t, "message 999", m.Message)
+
+	// test the writer rejects new logs once closed
+	require.Nil(t, w.Close())
+	_, e = w.Write([]byte("message"))
+	assert.NotNil(t, e)
+}
+
+func TestAsyncWriterFlush(t *testing.T) {
+	// queue several messages and flush them without closing the writer
+	b := NewBufferedWriter(JSON, true)
+	w := NewAsyncWriter(b, 100)
+	l := NewLogger(JSON, true, w)
+	for i := 0; i < 50; i++ {
+		l.Infof("message %d", i)
+	}
+	require.Nil(t, w.Flush())
+	assert.Len(t, b.Buffer(), 50)
+
+	// test the writer still accepts logs after flushing
+	l.Info("message 50")
+	require.Nil(t, w.Close())
+	assert.Len(t, b.Buffer(), 51)
+	assert.Nil(t, w.Flush())
+}
+
+// slowWriter defines a writer that delays each write, used to simulate an unresponsive destination.
+type slowWriter struct {
+	delay time.Duration
+}
+
+func (w *slowWriter) SetFormatting(format Format, noColor bool) {}
+
+func (w *slowWriter) Write(p []byte) (n int, err error) {
+	time.Sleep(w.delay)
+	return len(p), nil
+}
+
+func TestFlushWithTimeout(t *testing.T) {
+	w := NewAsyncWriter(&slowWriter{delay: 100 * time.Millisecond}, 10)
+	InitLoggerWithWriter(JSON, true, w)
+	SetGlobalLevel(InfoLevel)
+	defer InitLogger(Default)
+
+	// test the timeout is honored when the destination is slow
+	for i := 0; i < 5; i++ {
+		Infof("message %d", i)
+	}
+	start := time.Now()
+	assert.NotNil(t, FlushWithTimeout(50*time.Millisecond))
+	assert.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	// test all messages are drained within a sufficient timeout
+	assert.Nil(t, FlushWithTimeout(time.Second))
+	require.Nil(t, w.Close())
+}
+
+func TestFlushWithTimeoutWrapped(t *testing.T) {
+	w := NewAsyncWriter(&slowWriter{delay: 100 * time.Millisecond}, 10)
+	InitLoggerWithWriter(JSON, true, NewRedactingWriter(NewDedupWriter(w, time.Hour)))
+	SetGlobalLevel(InfoLevel)
+	defer InitLogger(Default)
+
+	// test the timeout applies to an async writer wrapped by other writers
+	for i := 0; i < 5; i++ {
+		Infof("message %d", i)
+	}
+	start := time.Now()
+	assert.NotNil(t, FlushWithTimeout(50*time.Millisecond))
+	assert.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	// test all messages are drained within a sufficient timeout
+	assert.Nil(t, FlushWithTimeout(time.Second))
+	require.Nil(t, w.Close())
+}
+
+// levelWriter defines a writer that records the level of each log written by WriteLevel, or NoLevel if written by
+// Write.
+type levelWriter struct {
+	levels []zerolog.Level
+}
+
+func (w *levelWriter) SetFormatting(format Format, noColor bool) {}
+
+func (w *levelWriter) Write(p []byte) (n int, err error) {
+	w.levels = append(w.levels, zerolog.NoLevel)
+	return len(p), nil
+}
+
+func (w *levelWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	w.levels = append(w.levels, level)
+	return len(p), nil
+}
+
+func TestAsyncWriterLevel(t *testing.T) {
+	lw := &levelWriter{}
+	w := NewAsyncWriter(lw, 10)
+	l := NewLogger(JSON, true, w)
+
+	// test the level is passed to the wrapped writer
+	l.Warn("Snapshot missing")
+	l.Error("Cannot connect")
+	_, err := w.Write([]byte(`{"message":"raw"}`))
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+	assert.Equal(t, []zerolog.Level{zerolog.WarnLevel, zerolog.ErrorLevel, zerolog.NoLevel}, lw.levels)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
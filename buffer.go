@@ -8,14 +8,39 @@ package log
 //======================================================================================================================
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
 )
 
 //======================================================================================================================
 // endregion
 //======================================================================================================================
 
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _ansiPattern matches ANSI color codes, used to strip color coding from buffered logs.
+var _ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// _flushDiagnostics indicates whether Flush announces the number of buffered logs, see SetFlushDiagnostics.
+var _flushDiagnostics bool
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
 //======================================================================================================================
 // region Public Types
 //======================================================================================================================
@@ -24,9 +49,172 @@ import (
 type Buffer []string
 
 // BufferedWriter captures application logs and stores them in a local buffer. Log lines are separated by newline
-// characters and are added one at a time.
+// characters and are added one at a time, although a log with line feeds embedded in its message is captured as a
+// single entry. The buffer grows without bound, unless a capacity is set by
+// NewBufferedWriterWithCapacity. A BufferedWriter is safe for concurrent use.
 type BufferedWriter struct {
 	writer *ConsoleWriter
+	max    int
+	clean  bool       // strip ANSI escape codes from the captured lines
+	mu     sync.Mutex // protects the writer and the underlying buffer
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// lineLevel parses the level of a buffered log line using the given format. It returns false if the level cannot be
+// determined. In Default format, lines without a level indicator are considered to be Info logs.
+func lineLevel(format Format, line string) (Level, bool) {
+	var label string
+	switch format {
+	case JSON:
+		var evt map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &evt); err != nil {
+			return 0, false
+		}
+		label, _ = evt[zerolog.LevelFieldName].(string)
+	case Logfmt:
+		label = strings.TrimPrefix(strings.SplitN(line, " ", 2)[0], "level=")
+	case CSV:
+		record, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil || len(record) < 2 {
+			return 0, false
+		}
+		label = record[1]
+	case Pretty:
+		return prettyLineLevel(_ansiPattern.ReplaceAllString(line, ""))
+	default:
+		return defaultLineLevel(_ansiPattern.ReplaceAllString(line, "")), true
+	}
+
+	l, err := zerolog.ParseLevel(strings.ToLower(label))
+	if err != nil || label == "" {
+		return 0, false
+	}
+	return Level(l), true
+}
+
+// capture writes the log to the buffer using the write function, which renders the log in the given format. A log with
+// line feeds embedded in its message is captured as a single entry. It strips ANSI escape codes from the captured lines
+// if needed, and drops the oldest logs when the capacity of the BufferedWriter is exceeded. The caller must hold the
+// lock.
+func (b *BufferedWriter) capture(format Format, write func([]byte) (int, error), p []byte) (n int, err error) {
+	v, ok := b.writer.output.(*Buffer)
+	if !ok {
+		return write(p)
+	}
+
+	start := len(*v)
+	n, err = write(p)
+	if lines := eventLines(format, p); lines > 1 {
+		// join the lines of the message, which precede a stack trace or follow a CSV header
+		first := start
+		if format == CSV && len(*v)-lines > start {
+			first = len(*v) - lines
+		}
+		end := first + lines
+		if end > len(*v) {
+			end = len(*v)
+		}
+		if end-first > 1 {
+			entry := strings.Join((*v)[first:end], "\n")
+			*v = append(append((*v)[:first], entry), (*v)[end:]...)
+		}
+	}
+	if b.clean {
+		for i := start; i < len(*v); i++ {
+			(*v)[i] = _ansiPattern.ReplaceAllString((*v)[i], "")
+		}
+	}
+	if b.max > 0 {
+		v.truncate(b.max)
+	}
+	return n, err
+}
+
+// eventLines returns the number of lines spanned by the rendered log in the given format, due to line feeds embedded in
+// its message. Default, Pretty, and CSV format render these line feeds as-is, while the other formats escape them.
+// Lines added by a stack trace are not counted.
+func eventLines(format Format, p []byte) int {
+	if (format != Default && format != Pretty && format != CSV) || !bytes.Contains(p, []byte(`\n`)) {
+		return 1
+	}
+
+	var evt map[string]interface{}
+	if err := json.Unmarshal(p, &evt); err != nil {
+		return 1
+	}
+	msg, _ := evt[zerolog.MessageFieldName].(string)
+	n := strings.Count(msg, "\n")
+	if format == CSV {
+		e, _ := evt[zerolog.ErrorFieldName].(string)
+		n += strings.Count(e, "\n")
+	}
+	return n + 1
+}
+
+// flushWriter flushes a writer that queues logs internally, until the context is done. Writers that do not support a
+// context are flushed in the background, abandoning the wait once the context is done. Writers wrapping another writer
+// forward FlushContext, such that queued logs are drained within the context too.
+func flushWriter(ctx context.Context, w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ FlushContext(context.Context) error }:
+		return f.FlushContext(ctx)
+	case Flusher:
+		done := make(chan error, 1)
+		go func() { done <- f.Flush() }()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	default:
+		return nil
+	}
+}
+
+// cause returns the error of the held message. The error string is converted into an error if the original error
+// object is not available, such as for a message created by UnmarshalLog.
+func (m Message) cause() error {
+	if m.err == nil && m.Error != "" {
+		return errors.New(m.Error)
+	}
+	return m.err
+}
+
+// flushUntilError writes all buffered logs of the Logger instance if it is on hold until an error occurs, see
+// HoldUntilError.
+func (l *Logger) flushUntilError() {
+	l.mu.RLock()
+	flush := l.hold && l.untilError
+	l.mu.RUnlock()
+	if flush {
+		l.Flush()
+	}
+}
+
+// truncate drops the oldest logs from the Buffer, keeping at most max logs. The order of the remaining logs is
+// preserved.
+func (b *Buffer) truncate(max int) {
+	if excess := len(*b) - max; excess > 0 {
+		copy(*b, (*b)[excess:])
+		*b = (*b)[:max]
+	}
+}
+
+// writeBypass writes the log to the buffer in Default format, dropping the oldest logs when the capacity of the
+// BufferedWriter is exceeded.
+func (b *BufferedWriter) writeBypass(p []byte) (n int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.capture(Default, b.writer.writeBypass, p)
 }
 
 //======================================================================================================================
@@ -45,6 +233,23 @@ func NewBufferedWriter(format Format, noColor bool) *BufferedWriter {
 	return &b
 }
 
+// NewBufferedWriterClean creates a log writer that buffers logs in memory as plain text. ANSI escape codes are stripped
+// from each captured line, regardless of the color coding set by SetFormatting. Use NewBufferedWriterClean to assert
+// logs without having to account for color coding.
+func NewBufferedWriterClean(format Format) *BufferedWriter {
+	b := NewBufferedWriter(format, false)
+	b.clean = true
+	return b
+}
+
+// NewBufferedWriterWithCapacity creates a log writer that buffers up to max logs in memory. When the capacity is
+// exceeded, the oldest logs are dropped. A max value of zero or less disables the capacity.
+func NewBufferedWriterWithCapacity(format Format, noColor bool, max int) *BufferedWriter {
+	b := NewBufferedWriter(format, noColor)
+	b.max = max
+	return b
+}
+
 // Write implements the io.Writer interface for Buffer.
 func (b *Buffer) Write(p []byte) (n int, err error) {
 	// remove multiple line feeds
@@ -64,17 +269,63 @@ func (b *Buffer) Write(p []byte) (n int, err error) {
 
 // Buffer retrieves a copy of the local buffer managed by BufferedWriter.
 func (b *BufferedWriter) Buffer() Buffer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	if b.writer != nil && b.writer.output != nil {
 		if v, ok := b.writer.output.(*Buffer); ok {
-			return *v
+			return append(make(Buffer, 0, len(*v)), *v...)
 		}
 	}
 
 	return make(Buffer, 0)
 }
 
+// LevelAt retrieves the level of the buffered log at index i, parsed similar to LinesAtLevel. It returns false if the
+// index is out of range, or if the level cannot be determined.
+func (b *BufferedWriter) LevelAt(i int) (Level, bool) {
+	buffer := b.Buffer()
+	if i < 0 || i >= len(buffer) {
+		return 0, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.writer == nil {
+		return 0, false
+	}
+	return lineLevel(b.writer.format, buffer[i])
+}
+
+// LinesAtLevel retrieves the buffered logs with a level of at least min. The level of each log is parsed using the
+// current format of the BufferedWriter, logs without a recognizable level are skipped. Note that Default format omits
+// the level of Info logs. As such, any log in Default format without a level indicator is considered to be an Info
+// log, including continuation lines of multi-line messages. A level indicator is the exact label of the level, see
+// SetLevelLabels, followed by its padding. An Info message such as "Warn users" is therefore not mistaken for a Warn
+// log.
+func (b *BufferedWriter) LinesAtLevel(min Level) Buffer {
+	lines := make(Buffer, 0)
+	buffer := b.Buffer()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.writer == nil {
+		return lines
+	}
+
+	for _, line := range buffer {
+		if l, ok := lineLevel(b.writer.format, line); ok && l >= min {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
 // Reset removes all existing logs from the local buffer.
 func (b *BufferedWriter) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	if b.writer != nil {
 		buffer := make(Buffer, 0)
 		format := b.writer.format
@@ -85,34 +336,215 @@ func (b *BufferedWriter) Reset() {
 
 // SetFormatting updates the log format and color coding of an existing BufferedWriter.
 func (b *BufferedWriter) SetFormatting(format Format, noColor bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.writer.SetFormatting(format, noColor)
 }
 
-// Write implements the io.Writer interface for BufferedWriter.
+// Write implements the io.Writer interface for BufferedWriter. It drops the oldest logs when the capacity of the
+// BufferedWriter is exceeded.
 func (b *BufferedWriter) Write(p []byte) (n int, err error) {
-	return b.writer.Write(p)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.capture(b.writer.format, b.writer.Write, p)
 }
 
-// Flush writes all buffered logs to the active logger and empties the buffer. Subsequent logs are no longer buffered.
+// Flush writes all buffered logs of the Logger instance to its writers and empties the buffer. The logs are rendered in
+// the format active at the time of flushing. Subsequent logs are no longer buffered.
+func (l *Logger) Flush() {
+	l.FlushN()
+}
+
+// FlushN writes all buffered logs of the Logger instance to its writers and empties the buffer, similar to Flush. The
+// logs are rendered in the format active at the time of flushing, not the format active when they were held. It
+// returns the number of logs written, excluding logs dropped by the global level or a rate limit. Logs dropped by
+// sampling are counted as written. The debug message announcing the flush, see SetFlushDiagnostics, is not counted.
+// The buffer is swapped out under the lock, while the logs are written after releasing it. As such, logs written
+// concurrently while flushing are not blocked, and may be interleaved with the buffered logs.
+func (l *Logger) FlushN() int {
+	// swap out the buffer and remove hold to display next message immediately
+	l.mu.Lock()
+	buffer := l.buffer
+	l.buffer = make([]Message, 0)
+	l.hold = false
+	l.untilError = false
+	l.mu.Unlock()
+
+	// flush the buffered logs without holding the lock, so writers may log themselves
+	n := 0
+	if len(buffer) > 0 {
+		if _flushDiagnostics {
+			l.emit(DebugLevel, nil, fmt.Sprintf("Flushing buffer with %d log(s)", len(buffer)), nil, nil)
+		}
+		for i := range buffer {
+			m := &buffer[i]
+			if l.emit(m.Level, m.fields, m.Message, m.cause(), &m.tags) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Discard removes all buffered logs of the Logger instance without writing them. The hold state is not changed.
+func (l *Logger) Discard() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buffer = make([]Message, 0)
+}
+
+// HeldCount returns the number of logs currently buffered by the Logger instance.
+func (l *Logger) HeldCount() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.buffer)
+}
+
+// HeldMessages returns a copy of the logs currently buffered by the Logger instance, without flushing them.
+func (l *Logger) HeldMessages() []Message {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	messages := make([]Message, len(l.buffer))
+	copy(messages, l.buffer)
+	return messages
+}
+
+// Hold instructs the Logger instance to buffer all incoming logs instead of writing them to its output stream. Use
+// Flush to write the buffered logs and to empty the buffer.
+func (l *Logger) Hold() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hold = true
+	l.untilError = false
+}
+
+// HoldUntilError instructs the Logger instance to buffer all incoming logs until an Error or Fatal log occurs. The
+// buffered logs are then written to the output stream, followed by the error itself. Subsequent logs are no longer
+// buffered. When no error occurs, Close either writes or discards the buffered logs, as indicated by flushOnClose.
+// Use Flush or Discard to end the hold explicitly.
+func (l *Logger) HoldUntilError(flushOnClose bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hold = true
+	l.untilError = true
+	l.flushOnClose = flushOnClose
+}
+
+// PreviewHeld renders the held messages of the Logger instance using the given format, without flushing or clearing
+// them. The messages are rendered similar to Flush, including fields, base fields, and the global level. Timestamps
+// reflect the time of rendering, as Flush timestamps the messages when they are written too. Sequence numbers and
+// goroutine tags are rendered as recorded when the messages were held. The messages are rendered directly by the
+// formatter, as such sampling, rate limits, and hooks do not apply. Hooks run only once the messages are flushed, to
+// avoid side effects.
+func (l *Logger) PreviewHeld(format Format) []string {
+	w := NewBufferedWriter(format, l.noColor)
+	handler := zerolog.New(w)
+	if _timestamp {
+		handler = handler.With().Timestamp().Logger()
+	}
+	handler = withBaseFields(handler)
+	for _, m := range l.HeldMessages() {
+		writeEvent(&handler, m.Level, m.fields, m.Message, m.cause(), &m.tags)
+	}
+	return w.Buffer()
+}
+
+// SetHoldCapacity limits the number of logs buffered by the Logger instance while on hold. When the capacity is
+// exceeded, the oldest logs are dropped. A max value of zero or less disables the capacity.
+func (l *Logger) SetHoldCapacity(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.holdMax = max
+	if max > 0 && len(l.buffer) > max {
+		l.buffer = append(make([]Message, 0, max), l.buffer[len(l.buffer)-max:]...)
+	}
+}
+
+// Discard removes all buffered logs of the active logger without writing them. Use Discard in a transactional flow to
+// drop the accumulated logs when an operation succeeds. The hold state is not changed.
+func Discard() {
+	_logger.Discard()
+}
+
+// Flush writes all buffered logs to the active logger and empties the buffer. The logs are rendered in the format
+// active at the time of flushing, for example when the format is changed by SetFormatting after Hold. Subsequent logs
+// are no longer buffered.
 func Flush() {
-	_logger.hold = false // remove hold to display next message immediately
+	_logger.Flush()
+}
+
+// FlushN writes all buffered logs to the active logger and empties the buffer, similar to Flush. It returns the number
+// of logs written.
+func FlushN() int {
+	return _logger.FlushN()
+}
 
-	// flush the buffered logs
-	if len(_logger.buffer) > 0 {
-		Debugf("Flushing buffer with %d log(s)", len(_logger.buffer))
-		for _, l := range _logger.buffer {
-			log(l.Level, l.Message, l.err)
+// FlushWithTimeout writes all buffered logs to the active logger, similar to Flush, and flushes any writers that queue
+// logs internally, such as AsyncWriter and HTTPWriter. It returns an error once the timeout elapses, abandoning the
+// writers that have not finished. As such, some queued logs may be dropped on timeout. Use FlushWithTimeout during
+// shutdown to avoid blocking indefinitely when a destination is unresponsive.
+func FlushWithTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	Flush()
+	var errs multiError
+	for _, w := range _logger.writers {
+		if err := flushWriter(ctx, w); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	// clear the buffer
-	_logger.buffer = make([]Message, 0)
+	if ctx.Err() != nil {
+		return fmt.Errorf("Cannot flush logs within %s", d)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 // Hold instructs the active logger to buffer all incoming logs instead of writing them to current output stream. Use
 // Flush to write the buffered logs and to empty the buffer.
 func Hold() {
-	_logger.hold = true
+	_logger.Hold()
+}
+
+// HoldUntilError instructs the active logger to buffer all incoming logs until an Error or Fatal log occurs, for
+// example to suppress the progress of a command-line tool unless something goes wrong. The buffered logs are then
+// written, followed by the error itself. When no error occurs, Close either writes or discards the buffered logs, as
+// indicated by flushOnClose.
+func HoldUntilError(flushOnClose bool) {
+	_logger.HoldUntilError(flushOnClose)
+}
+
+// HeldCount returns the number of logs currently buffered by the active logger.
+func HeldCount() int {
+	return _logger.HeldCount()
+}
+
+// HeldMessages returns a copy of the logs currently buffered by the active logger, without flushing them.
+func HeldMessages() []Message {
+	return _logger.HeldMessages()
+}
+
+// PreviewHeld renders the logs currently buffered by the active logger using the given format, without flushing or
+// clearing them. Use PreviewHeld to show the logs before they are written, for example to ask for confirmation.
+func PreviewHeld(format Format) []string {
+	return _logger.PreviewHeld(format)
+}
+
+// SetFlushDiagnostics enables or disables a debug message announcing the number of buffered logs when flushing, such as
+// "Flushing buffer with 3 log(s)". The message is disabled by default.
+func SetFlushDiagnostics(enabled bool) {
+	_flushDiagnostics = enabled
+}
+
+// SetHoldCapacity limits the number of logs buffered by the active logger while on hold. When the capacity is
+// exceeded, the oldest logs are dropped. A max value of zero or less disables the capacity.
+func SetHoldCapacity(max int) {
+	_logger.SetHoldCapacity(max)
 }
 
 //======================================================================================================================
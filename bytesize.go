@@ -0,0 +1,203 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// byteSizesFieldName defines the name of the field listing the paths of the fields created by Bytes. The field is
+// consumed when rendering logs in Default and Pretty format, and omitted in the other formats.
+const byteSizesFieldName = "_bytes"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Constants
+//======================================================================================================================
+
+// Defines the units used to render byte sizes, see SetByteUnit.
+const (
+	// BinaryUnit renders byte sizes using powers of 1024, such as "1.0 MiB".
+	BinaryUnit ByteUnit = iota
+
+	// DecimalUnit renders byte sizes using powers of 1000, such as "1.0 MB".
+	DecimalUnit
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _byteUnit defines the unit used to render byte sizes in Default and Pretty format.
+var _byteUnit = BinaryUnit
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// ByteUnit defines the type of units used to render byte sizes, either BinaryUnit or DecimalUnit.
+type ByteUnit int
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// byteSize defines the value of a field created by Bytes, marking the value as a size in bytes.
+type byteSize int64
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// formatBytes renders n as human-readable byte size using the configured unit, for example "1.5 MiB". Values below
+// one kilobyte, including zero and negative values, are rendered as plain bytes, such as "512 B".
+func formatBytes(n int64) string {
+	base, units := int64(1024), []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	if _byteUnit == DecimalUnit {
+		base, units = 1000, []string{"kB", "MB", "GB", "TB", "PB", "EB"}
+	}
+	if n < base {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := base, 0
+	for m := n / base; m >= base && exp < len(units)-1; m /= base {
+		div *= base
+		exp++
+	}
+	return fmt.Sprintf("%.1f %s", float64(n)/float64(div), units[exp])
+}
+
+// byteSizePaths returns the paths of the fields created by Bytes, including fields nested in a group. The keys of
+// nested fields are joined with a dot, such as "stats.read".
+func byteSizePaths(fields []Field, prefix string) []string {
+	var paths []string
+	for _, f := range fields {
+		switch v := f.Value.(type) {
+		case byteSize:
+			paths = append(paths, prefix+f.Key)
+		case fieldGroup:
+			paths = append(paths, byteSizePaths(v, prefix+f.Key+".")...)
+		}
+	}
+	return paths
+}
+
+// humanizeBytes replaces the integer values of the fields listed by the byte sizes field with human-readable byte
+// sizes, and removes the byte sizes field from the event. It returns true if the event contains byte sizes.
+func humanizeBytes(evt map[string]interface{}) bool {
+	paths, ok := evt[byteSizesFieldName].([]interface{})
+	if !ok {
+		return false
+	}
+	delete(evt, byteSizesFieldName)
+
+	for _, p := range paths {
+		path, _ := p.(string)
+		keys := strings.Split(path, ".")
+		parent := evt
+		for _, k := range keys[:len(keys)-1] {
+			if parent, ok = parent[k].(map[string]interface{}); !ok {
+				break
+			}
+		}
+		if parent == nil {
+			continue
+		}
+		key := keys[len(keys)-1]
+		if value, ok := parent[key].(json.Number); ok {
+			if n, err := value.Int64(); err == nil {
+				parent[key] = formatBytes(n)
+			}
+		}
+	}
+	return true
+}
+
+// stripByteSizes removes the byte sizes field from the JSON-formatted log p, keeping the remaining log as-is. It
+// returns p unchanged if the log does not contain the field.
+func stripByteSizes(p []byte) []byte {
+	marker := []byte(`,"` + byteSizesFieldName + `":[`)
+	start := bytes.Index(p, marker)
+	if start < 0 {
+		return p
+	}
+
+	// find the end of the list, skipping any brackets in quoted paths
+	quoted, escaped := false, false
+	for i := start + len(marker); i < len(p); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && p[i] == '\\':
+			escaped = true
+		case p[i] == '"':
+			quoted = !quoted
+		case !quoted && p[i] == ']':
+			return append(append(make([]byte, 0, len(p)), p[:start]...), p[i+1:]...)
+		}
+	}
+	return p
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// Bytes returns a field that represents a size in bytes, for example the size of a file. JSON and Logfmt format render
+// the size as integer, such as "size":1048576, while Default and Pretty format render the size in human-readable form,
+// such as size="1.0 MiB". Use SetByteUnit to choose between binary and decimal units. The sizes are marked by an
+// internal "_bytes" field, which is omitted by the writers of this package. Custom writers receiving the JSON-formatted
+// logs as-is may encounter the field. Note that fields returned by hooks registered with AddHook are not marked.
+func Bytes(key string, n int64) Field {
+	return Field{Key: key, Value: byteSize(n)}
+}
+
+// SetByteUnit sets the unit used to render byte sizes in Default and Pretty format, either BinaryUnit (KiB, MiB, et
+// al.) or DecimalUnit (kB, MB, et al.). Binary units are used by default.
+func SetByteUnit(unit ByteUnit) {
+	_byteUnit = unit
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
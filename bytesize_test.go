@@ -0,0 +1,117 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestFormatBytes(t *testing.T) {
+	defer SetByteUnit(BinaryUnit)
+
+	tests := []struct {
+		n        int64
+		unit     ByteUnit
+		expected string
+	}{
+		{n: 0, unit: BinaryUnit, expected: "0 B"},
+		{n: -2048, unit: BinaryUnit, expected: "-2048 B"},
+		{n: 1023, unit: BinaryUnit, expected: "1023 B"},
+		{n: 1024, unit: BinaryUnit, expected: "1.0 KiB"},
+		{n: 1536, unit: BinaryUnit, expected: "1.5 KiB"},
+		{n: 1048576, unit: BinaryUnit, expected: "1.0 MiB"},
+		{n: 5 << 30, unit: BinaryUnit, expected: "5.0 GiB"},
+		{n: 1 << 62, unit: BinaryUnit, expected: "4.0 EiB"},
+		{n: 999, unit: DecimalUnit, expected: "999 B"},
+		{n: 1000, unit: DecimalUnit, expected: "1.0 kB"},
+		{n: 1048576, unit: DecimalUnit, expected: "1.0 MB"},
+		{n: 2500000000, unit: DecimalUnit, expected: "2.5 GB"},
+	}
+
+	for _, tc := range tests {
+		SetByteUnit(tc.unit)
+		assert.Equal(t, tc.expected, formatBytes(tc.n))
+	}
+}
+
+func TestBytes(t *testing.T) {
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{format: Default, expected: `Uploaded file file=backup.tar size="1.0 MiB" stats=[read="512 B"]`},
+		{format: Pretty, expected: `| INFO   | Uploaded file file=backup.tar size="1.0 MiB" stats=[read="512 B"]`},
+		{format: JSON, expected: `{"level":"info","file":"backup.tar","size":1048576,"stats":{"read":512},` +
+			`"message":"Uploaded file"}`},
+		{format: Logfmt, expected: `level=info msg="Uploaded file" file=backup.tar size=1048576 stats.read=512`},
+	}
+
+	// test byte sizes are human-readable in Default and Pretty format only
+	for _, tc := range tests {
+		w := NewBufferedWriter(tc.format, true)
+		l := NewLogger(tc.format, true, w)
+		l.With(Field{Key: "file", Value: "backup.tar"}, Bytes("size", 1048576), Dict("stats", Bytes("read", 512))).
+			Info("Uploaded file")
+		assert.Equal(t, Buffer{tc.expected}, w.Buffer())
+	}
+}
+
+func TestBytesScope(t *testing.T) {
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	w := NewBufferedWriter(Default, true)
+	l := NewLogger(Default, true, w)
+
+	// test an integer field with the same key as a byte size is rendered as-is
+	l.With(Bytes("size", 2048)).Info("Uploaded file")
+	l.With(Any("size", 2048)).Info("Listed files")
+	assert.Equal(t, Buffer{`Uploaded file size="2.0 KiB"`, "Listed files size=2048"}, w.Buffer())
+
+	// test the marker is omitted in JSON formats
+	for _, f := range []Format{JSON, JSONIndent} {
+		w := NewBufferedWriter(f, true)
+		NewLogger(f, true, w).With(Bytes("size", 2048)).Info("Uploaded file")
+		require.NotEmpty(t, w.Buffer())
+		assert.NotContains(t, strings.Join(w.Buffer(), "\n"), byteSizesFieldName)
+	}
+}
+
+func TestStripByteSizes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: `{"level":"info","size":1}`, expected: `{"level":"info","size":1}`},
+		{input: `{"level":"info","size":1,"_bytes":["size"]}`, expected: `{"level":"info","size":1}`},
+		{input: `{"level":"info","a]":1,"_bytes":["a]","b\"]"],"message":"x"}`,
+			expected: `{"level":"info","a]":1,"message":"x"}`},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, string(stripByteSizes([]byte(tc.input))))
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,77 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// errorChainFieldName defines the name of the field containing the chain of wrapped errors.
+const errorChainFieldName = "errors"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _errorChain indicates whether the chain of wrapped errors is logged.
+var _errorChain bool
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// errorChain returns the messages of the error and each error it wraps, starting with the error itself. It returns nil
+// if the error does not wrap another error.
+func errorChain(err error) []string {
+	if errors.Unwrap(err) == nil {
+		return nil
+	}
+
+	var chain []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, e.Error())
+	}
+	return chain
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetErrorChain enables or disables logging the chain of wrapped errors. When enabled, logs of errors that wrap other
+// errors include an "errors" field, listing the message of each error in the chain as found by errors.Unwrap. The
+// "error" field is kept for compatibility. The chain is omitted in Default and Pretty format, as the error message
+// typically includes the messages of the wrapped errors already, such as error="outer: inner: root". Errors that do
+// not wrap other errors are logged as-is.
+func SetErrorChain(enabled bool) {
+	_errorChain = enabled
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
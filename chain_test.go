@@ -0,0 +1,65 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSetErrorChain(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	root := errors.New("root")
+	err := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", root))
+
+	// test the chain is omitted when disabled
+	ErrorE(err, "Cannot connect")
+	require.Len(t, w.Buffer(), 1)
+	assert.NotContains(t, w.Buffer()[0], `"errors"`)
+
+	// test the full chain is logged when enabled
+	SetErrorChain(true)
+	defer SetErrorChain(false)
+	ErrorE(err, "Cannot connect")
+	require.Len(t, w.Buffer(), 2)
+	assert.Regexp(t, `^{"level":"error","errors":\["outer: inner: root","inner: root","root"\],`+
+		`"error":"outer: inner: root","time":"\S+","message":"Cannot connect"}$`, w.Buffer()[1])
+
+	// test non-wrapped and nil errors behave as before
+	ErrorE(root, "Cannot connect")
+	ErrorE(nil, "Cannot connect")
+	require.Len(t, w.Buffer(), 4)
+	assert.Regexp(t, `^{"level":"error","error":"root","time":"\S+","message":"Cannot connect"}$`, w.Buffer()[2])
+	assert.Regexp(t, `^{"level":"error","time":"\S+","message":"Cannot connect"}$`, w.Buffer()[3])
+
+	// test the chain is omitted in Default and Pretty format
+	for _, format := range []Format{Default, Pretty} {
+		w.Reset()
+		SetFormatting(format, true)
+		ErrorE(err, "Cannot connect")
+		require.Len(t, w.Buffer(), 1)
+		assert.Contains(t, w.Buffer()[0], `Cannot connect error="outer: inner: root"`)
+		assert.NotContains(t, w.Buffer()[0], "errors")
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,89 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// channelWriter implements a log writer that parses JSON-formatted logs into messages and sends them on a channel. It
+// drops messages when the channel is full.
+type channelWriter struct {
+	ch     chan Message
+	closed bool
+	mu     sync.Mutex // protects the channel from being closed while writing
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewChannelWriter creates a log writer that sends each log as parsed Message on the returned channel, for example to
+// stream logs live to a web interface. The channel buffers up to buf messages. Messages are dropped when the channel
+// is full, so a slow consumer never blocks logging. Logs are parsed in JSON format, regardless of the format of the
+// Logger. Call Close on the writer to close the channel.
+func NewChannelWriter(buf int) (Writer, <-chan Message) {
+	if buf < 0 {
+		buf = 0
+	}
+	w := &channelWriter{ch: make(chan Message, buf)}
+	return w, w.ch
+}
+
+// Close closes the channel of the channelWriter. Subsequent writes return an error. Calling Close more than once has
+// no effect.
+func (w *channelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.closed {
+		w.closed = true
+		close(w.ch)
+	}
+	return nil
+}
+
+// SetFormatting is ignored by channelWriter, as logs are always parsed in JSON format.
+func (w *channelWriter) SetFormatting(format Format, noColor bool) {}
+
+// Write implements the io.Writer interface for channelWriter. It parses the JSON-formatted log into a Message and sends
+// it on the channel, unless the channel is full. Logs that cannot be parsed return an error.
+func (w *channelWriter) Write(p []byte) (n int, err error) {
+	m, err := UnmarshalLog(p)
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errors.New("Cannot write to closed channel writer")
+	}
+	select {
+	case w.ch <- *m:
+	default:
+	}
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
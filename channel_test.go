@@ -0,0 +1,55 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestChannelWriter(t *testing.T) {
+	w, ch := NewChannelWriter(2)
+	l := NewLogger(Pretty, true, w)
+	SetGlobalLevel(InfoLevel)
+
+	// test the logs arrive as parsed messages, dropping logs when the channel is full
+	l.Info("Listing snapshots")
+	l.WarnE(errors.New("not found"), "Snapshot missing")
+	l.Error("Cannot connect")
+	require.Len(t, ch, 2)
+	m := <-ch
+	assert.Equal(t, InfoLevel, m.Level)
+	assert.Equal(t, "Listing snapshots", m.Message)
+	assert.False(t, m.Time.IsZero())
+	m = <-ch
+	assert.Equal(t, WarnLevel, m.Level)
+	assert.Equal(t, "Snapshot missing", m.Message)
+	assert.Equal(t, "not found", m.Error)
+
+	// test the channel is closed
+	require.Nil(t, l.Close())
+	_, ok := <-ch
+	assert.False(t, ok)
+	_, err := w.Write([]byte(`{"level":"info","time":"2020-12-17T06:12:57Z","message":"Listing snapshots"}`))
+	assert.EqualError(t, err, "Cannot write to closed channel writer")
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
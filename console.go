@@ -8,9 +8,18 @@ package log
 //======================================================================================================================
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -20,6 +29,61 @@ import (
 // endregion
 //======================================================================================================================
 
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// Defines ANSI color codes used by the console writers.
+const (
+	colorRed      = 31
+	colorGreen    = 32
+	colorYellow   = 33
+	colorMagenta  = 35
+	colorDarkGray = 90
+)
+
+// defaultLevelWidth defines the default minimum width of the level labels in Default and Pretty format.
+const defaultLevelWidth = 6
+
+// defaultPrettySeparator defines the default separator enclosing the level labels in Pretty format.
+const defaultPrettySeparator = "|"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _levelColors defines the ANSI color codes of the levels in Default and Pretty format.
+var _levelColors = defaultLevelColors()
+
+// _levelLabels defines the labels of the levels in Default and Pretty format.
+var _levelLabels = defaultLevelLabels()
+
+// _levelWidth defines the minimum width of the level labels in Default and Pretty format, see SetLevelWidth.
+var _levelWidth = defaultLevelWidth
+
+// _prettySeparator defines the separator enclosing the level labels in Pretty format, see SetPrettySeparator.
+var _prettySeparator = defaultPrettySeparator
+
+// _prettyLevelPattern matches the level label enclosed by the separator in Pretty format, such as "| INFO   |".
+var _prettyLevelPattern = prettyLevelPattern(defaultPrettySeparator)
+
+// _relativeTime indicates whether Pretty format shows the time elapsed since the start of the process.
+var _relativeTime bool
+
+// _startTime defines the start of the process, used to compute relative timestamps.
+var _startTime = time.Now()
+
+// _nilOutputWarning ensures the warning about a nil output of a ConsoleWriter is shown only once.
+var _nilOutputWarning sync.Once
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
 //======================================================================================================================
 // region Public Types
 //======================================================================================================================
@@ -33,6 +97,39 @@ type ConsoleWriter struct {
 	writer  io.Writer
 }
 
+// csvWriter implements a log writer that converts JSON-formatted logs produced by zerolog into comma-separated values.
+// It writes a header row before the first log.
+type csvWriter struct {
+	out    io.Writer
+	header bool
+	mu     sync.Mutex
+}
+
+// indentWriter implements a log writer that indents JSON-formatted logs produced by zerolog across multiple lines.
+type indentWriter struct {
+	out io.Writer
+}
+
+// prepareWriter implements a log writer that prepares JSON-formatted logs for rendering in Default or Pretty format,
+// before passing them to the next writer. It converts the values of fields created by Bytes into human-readable byte
+// sizes and prefixes the message with the component set by Named.
+type prepareWriter struct {
+	next   io.Writer
+	format Format
+}
+
+// jsonWriter implements a log writer that writes JSON-formatted logs produced by zerolog as-is, omitting the internal
+// field that marks byte sizes.
+type jsonWriter struct {
+	out io.Writer
+}
+
+// logfmtWriter implements a log writer that converts JSON-formatted logs produced by zerolog into the logfmt
+// convention.
+type logfmtWriter struct {
+	out io.Writer
+}
+
 //======================================================================================================================
 // endregion
 //======================================================================================================================
@@ -47,7 +144,10 @@ func newWriter(format Format, noColor bool, out io.Writer) io.Writer {
 	// customize the writer if default or pretty formatting is used
 	switch format {
 	case Format(Default):
-		writer := zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339, NoColor: noColor}
+		writer := zerolog.ConsoleWriter{Out: out, TimeFormat: _timeFormat, NoColor: noColor}
+		writer.FieldsExclude = []string{zerolog.ErrorStackFieldName, errorChainFieldName}
+		writer.FormatExtra = formatStack
+		writer.FormatFieldValue = formatFieldValue
 		writer.FormatTimestamp = func(i interface{}) string {
 			return ""
 		}
@@ -56,23 +156,481 @@ func newWriter(format Format, noColor bool, out io.Writer) io.Writer {
 			if ok && v == "info" {
 				return ""
 			}
-			return strings.ToUpper(fmt.Sprintf("%-6s", i))
+			label := levelLabel(i)
+			return colorize(label, levelColor(i), noColor) + padding(label, labelWidth())
 		}
-		return writer
+		return &prepareWriter{next: writer, format: format}
 
 	case Format(Pretty):
-		writer := zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339, NoColor: noColor}
-		writer.FormatTimestamp = nil
+		writer := zerolog.ConsoleWriter{Out: out, TimeFormat: _timeFormat, NoColor: noColor}
+		writer.FieldsExclude = []string{zerolog.ErrorStackFieldName, errorChainFieldName}
+		writer.FormatExtra = formatStack
+		writer.FormatFieldValue = formatFieldValue
+		writer.FormatTimestamp = formatTimestamp(noColor)
 		writer.FormatLevel = func(i interface{}) string {
-			return strings.ToUpper(fmt.Sprintf("| %-6s |", i))
+			label := levelLabel(i)
+			level := colorize(label, levelColor(i), noColor) + padding(label, labelWidth())
+			if _prettySeparator == "" {
+				return level
+			}
+			return _prettySeparator + " " + level + " " + _prettySeparator
+		}
+		return &prepareWriter{next: writer, format: format}
+
+	case Format(Logfmt):
+		return &logfmtWriter{out: out}
+
+	case Format(JSONIndent):
+		return &indentWriter{out: out}
+
+	case Format(CSV):
+		return &csvWriter{out: out}
+
+	default:
+		return &jsonWriter{out: out}
+	}
+}
+
+// nameComponent moves the component set by Named into the message, using a bracketed prefix in Pretty format and a
+// colon-separated prefix in Default format. It returns true if the event contains a component.
+func nameComponent(evt map[string]interface{}, format Format) bool {
+	c, ok := evt[componentFieldName].(string)
+	if !ok {
+		return false
+	}
+	delete(evt, componentFieldName)
+
+	msg, _ := evt[zerolog.MessageFieldName].(string)
+	if format == Pretty {
+		evt[zerolog.MessageFieldName] = "[" + c + "] " + msg
+	} else {
+		evt[zerolog.MessageFieldName] = c + ": " + msg
+	}
+	return true
+}
+
+// padding returns the spaces needed to pad the string to the given width. Padding is computed separately from any
+// color coding, as ANSI escape codes do not take up space.
+func padding(s string, width int) string {
+	if n := width - len(s); n > 0 {
+		return strings.Repeat(" ", n)
+	}
+	return ""
+}
+
+// appendLogfmtValue appends a key/value pair to the buffer using logfmt conventions. Nested objects are flattened by
+// joining the keys with a dot.
+func appendLogfmtValue(buf *bytes.Buffer, key string, value interface{}) {
+	if m, ok := value.(map[string]interface{}); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
 		}
-		return writer
+		sort.Strings(keys)
+		for _, k := range keys {
+			appendLogfmtValue(buf, key+"."+k, m[k])
+		}
+		return
+	}
+
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	case nil:
+		s = ""
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(b)
+		}
+	}
+
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	if needsLogfmtQuote(s) {
+		buf.WriteString(strconv.Quote(s))
+	} else {
+		buf.WriteString(s)
+	}
+}
+
+// colorize wraps the string in the ANSI color code, unless noColor is set or the color code is zero.
+func colorize(s string, color int, noColor bool) string {
+	if noColor || color == 0 || s == "" {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", color, s)
+}
+
+// defaultLevelColors returns the default ANSI color codes of the levels in Default and Pretty format.
+func defaultLevelColors() map[Level]int {
+	return map[Level]int{
+		TraceLevel: colorMagenta,
+		DebugLevel: colorYellow,
+		InfoLevel:  colorGreen,
+		WarnLevel:  colorRed,
+		ErrorLevel: colorRed,
+		FatalLevel: colorRed,
+		PanicLevel: colorRed,
+	}
+}
+
+// defaultLevelLabels returns the default labels of the levels in Default and Pretty format, being the uppercase names
+// of the levels.
+func defaultLevelLabels() map[Level]string {
+	return map[Level]string{
+		TraceLevel: "TRACE",
+		DebugLevel: "DEBUG",
+		InfoLevel:  "INFO",
+		WarnLevel:  "WARN",
+		ErrorLevel: "ERROR",
+		FatalLevel: "FATAL",
+		PanicLevel: "PANIC",
+	}
+}
+
+// formatFieldValue renders the value of a field for zerolog.ConsoleWriter. Nested objects and arrays, which are passed
+// as marshaled JSON, are rendered as bracketed group of key=value pairs sorted by key, and as bracketed list of values
+// respectively. Other values are rendered as-is.
+func formatFieldValue(i interface{}) string {
+	b, ok := i.([]byte)
+	if !ok || len(b) == 0 || (b[0] != '{' && b[0] != '[') {
+		return fmt.Sprintf("%s", i)
+	}
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return string(b)
+	}
+	return formatValue(v)
+}
+
+// formatGroup renders a nested object as bracketed group of key=value pairs, sorted by key. Values that require
+// quoting are quoted.
+func formatGroup(m map[string]interface{}) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+formatValue(m[k]))
+	}
+	return "[" + strings.Join(pairs, " ") + "]"
+}
+
+// formatList renders an array as bracketed list of space-separated values.
+func formatList(l []interface{}) string {
+	values := make([]string, 0, len(l))
+	for _, v := range l {
+		values = append(values, formatValue(v))
+	}
+	return "[" + strings.Join(values, " ") + "]"
+}
 
+// formatValue renders a decoded JSON value for Default and Pretty format. Objects and arrays are bracketed, while
+// strings that require quoting are quoted.
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		return formatGroup(value)
+	case []interface{}:
+		return formatList(value)
+	case string:
+		if needsLogfmtQuote(value) {
+			return strconv.Quote(value)
+		}
+		return value
+	case json.Number:
+		return value.String()
 	default:
-		return out
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+}
+
+// formatTimestamp returns a zerolog.Formatter that renders the timestamp as produced by the logger, using the layout
+// configured by SetTimeFormat, or as the time elapsed since the start of the process if SetRelativeTime is enabled.
+// Unlike the zerolog default, an absent timestamp is omitted.
+func formatTimestamp(noColor bool) zerolog.Formatter {
+	return func(i interface{}) string {
+		if _relativeTime && i != nil {
+			return colorize(fmt.Sprintf("+%.3fs", time.Since(_startTime).Seconds()), colorDarkGray, noColor)
+		}
+
+		var t string
+		switch v := i.(type) {
+		case string:
+			t = v
+		case json.Number:
+			t = v.String()
+		}
+		return colorize(t, colorDarkGray, noColor)
 	}
 }
 
+// labelWidth returns the width of the level labels in Default and Pretty format, being the length of the longest label
+// plus one, with a minimum as set by SetLevelWidth.
+func labelWidth() int {
+	width := _levelWidth
+	for _, label := range _levelLabels {
+		if len(label)+1 > width {
+			width = len(label) + 1
+		}
+	}
+	return width
+}
+
+// levelColor returns the ANSI color code of the level as rendered by zerolog, or zero if no color is defined.
+func levelColor(i interface{}) int {
+	s, ok := i.(string)
+	if !ok {
+		return 0
+	}
+	l, err := zerolog.ParseLevel(s)
+	if err != nil {
+		return 0
+	}
+	return _levelColors[Level(l)]
+}
+
+// levelLabel returns the label of the level as rendered by zerolog, or the uppercase level if no label is defined.
+func levelLabel(i interface{}) string {
+	s := fmt.Sprintf("%s", i)
+	if l, err := zerolog.ParseLevel(s); err == nil && s != "" {
+		if label, ok := _levelLabels[Level(l)]; ok {
+			return label
+		}
+	}
+	return strings.ToUpper(s)
+}
+
+// needsLogfmtQuote returns true if the value contains characters that require quoting in logfmt, such as spaces,
+// equal signs, quotes, or control characters. Empty values are quoted too.
+func needsLogfmtQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// prettyLevelPattern returns a regular expression that matches the level label enclosed by the separator in Pretty
+// format. The separator must be surrounded by whitespace, so it is not confused with the timestamp. It returns nil if
+// the separator is blank.
+func prettyLevelPattern(sep string) *regexp.Regexp {
+	if strings.TrimSpace(sep) == "" {
+		return nil
+	}
+	q := regexp.QuoteMeta(sep)
+	return regexp.MustCompile(`(?:^|\s)` + q + ` (\S+) +` + q + `(?:\s|$)`)
+}
+
+// prettyLineLevel parses the level of a log line in Pretty format without color coding. The level is enclosed by the
+// separator set by SetPrettySeparator, or is one of the first two words of the line if the separator is blank. It
+// returns false if the level cannot be determined.
+func prettyLineLevel(line string) (Level, bool) {
+	if _prettyLevelPattern != nil {
+		m := _prettyLevelPattern.FindStringSubmatch(line)
+		if m == nil {
+			return 0, false
+		}
+		return parseLevelLabel(m[1])
+	}
+
+	words := strings.Fields(line)
+	for i := 0; i < len(words) && i < 2; i++ {
+		if l, ok := parseLevelLabel(words[i]); ok {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+// parseLevelLabel converts a level label, as rendered in Default and Pretty format, into a typed Level value. The
+// label must match a configured label exactly, including case. It returns false if the label is unknown.
+func parseLevelLabel(label string) (Level, bool) {
+	if label == "" {
+		return 0, false
+	}
+	for l, s := range _levelLabels {
+		if s == label {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+// defaultLineLevel parses the level of a log line in Default format without color coding. The line must start with a
+// configured label followed by its padding and a space, or by the end of the line. Other lines, including messages
+// that merely start with a level name such as "Warn users", are considered to be Info logs.
+func defaultLineLevel(line string) Level {
+	for l, label := range _levelLabels {
+		if label == "" || !strings.HasPrefix(line, label) {
+			continue
+		}
+		rest := line[len(label):]
+		if strings.HasPrefix(rest, padding(label, labelWidth())+" ") || strings.TrimSpace(rest) == "" {
+			return l
+		}
+	}
+	return InfoLevel
+}
+
+// writeBypass writes the log to the output of the ConsoleWriter in Default format without color coding.
+func (w *ConsoleWriter) writeBypass(p []byte) (n int, err error) {
+	return newWriter(Default, true, w.output).Write(p)
+}
+
+// Write implements the io.Writer interface for csvWriter. It expects a single JSON-formatted log message. Fields other
+// than the timestamp, level, message, and error are omitted. Values are quoted as defined by RFC 4180.
+func (w *csvWriter) Write(p []byte) (n int, err error) {
+	var evt map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	if err := d.Decode(&evt); err != nil {
+		return 0, fmt.Errorf("cannot decode event: %s", err)
+	}
+
+	names := []string{zerolog.TimestampFieldName, zerolog.LevelFieldName, zerolog.MessageFieldName,
+		zerolog.ErrorFieldName}
+	record := make([]string, len(names))
+	for i, name := range names {
+		if v, ok := evt[name]; ok {
+			record[i] = fmt.Sprint(v)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	buf := new(bytes.Buffer)
+	c := csv.NewWriter(buf)
+	if !w.header {
+		c.Write([]string{"time", "level", "message", "error"}) //nolint:errcheck // errors are reported by Flush
+	}
+	c.Write(record) //nolint:errcheck // errors are reported by Flush
+	c.Flush()
+	if err := c.Error(); err != nil {
+		return 0, err
+	}
+
+	if _, err := buf.WriteTo(w.out); err != nil {
+		return 0, err
+	}
+	w.header = true
+	return len(p), nil
+}
+
+// Write implements the io.Writer interface for indentWriter. It expects a single JSON-formatted log message.
+func (w *indentWriter) Write(p []byte) (n int, err error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimRight(stripByteSizes(p), "\n"), "", "  "); err != nil {
+		return 0, fmt.Errorf("cannot decode event: %s", err)
+	}
+	buf.WriteByte('\n')
+
+	if _, err := buf.WriteTo(w.out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Write implements the io.Writer interface for prepareWriter. It expects a single JSON-formatted log message. The log
+// is passed as-is if it requires no preparation.
+func (w *prepareWriter) Write(p []byte) (n int, err error) {
+	if !bytes.Contains(p, []byte(`"`+byteSizesFieldName+`":`)) &&
+		!bytes.Contains(p, []byte(`"`+componentFieldName+`":`)) {
+		return w.next.Write(p)
+	}
+
+	var evt map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	if err := d.Decode(&evt); err != nil {
+		return w.next.Write(p)
+	}
+	prepared := humanizeBytes(evt)
+	prepared = nameComponent(evt, w.format) || prepared
+	if !prepared {
+		return w.next.Write(p)
+	}
+
+	b, err := json.Marshal(evt)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.next.Write(append(b, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Write implements the io.Writer interface for jsonWriter. It expects a single JSON-formatted log message.
+func (w *jsonWriter) Write(p []byte) (n int, err error) {
+	if _, err := w.out.Write(stripByteSizes(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Write implements the io.Writer interface for logfmtWriter. It expects a single JSON-formatted log message.
+func (w *logfmtWriter) Write(p []byte) (n int, err error) {
+	var evt map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	if err := d.Decode(&evt); err != nil {
+		return 0, fmt.Errorf("cannot decode event: %s", err)
+	}
+	delete(evt, byteSizesFieldName)
+
+	// write the level, message, and timestamp first, followed by the error and any remaining fields
+	buf := new(bytes.Buffer)
+	fixed := []struct{ name, key string }{
+		{zerolog.LevelFieldName, "level"},
+		{zerolog.MessageFieldName, "msg"},
+		{zerolog.TimestampFieldName, "time"},
+		{zerolog.ErrorFieldName, "error"},
+	}
+	for _, f := range fixed {
+		if v, ok := evt[f.name]; ok {
+			appendLogfmtValue(buf, f.key, v)
+			delete(evt, f.name)
+		}
+	}
+
+	keys := make([]string, 0, len(evt))
+	for k := range evt {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendLogfmtValue(buf, k, evt[k])
+	}
+	buf.WriteByte('\n')
+
+	if _, err := buf.WriteTo(w.out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 //======================================================================================================================
 // endregion
 //======================================================================================================================
@@ -82,8 +640,16 @@ func newWriter(format Format, noColor bool, out io.Writer) io.Writer {
 //======================================================================================================================
 
 // NewConsoleWriter creates a new ConsoleWriter that supports Default formatting and Pretty formatting, next to the
-// default JSON formatting provided by zerolog.
+// default JSON formatting provided by zerolog. If out is nil, the writer uses os.Stdout instead and a one-time warning
+// is written to os.Stderr. Use NewConsoleWriterE to handle a nil output as error.
 func NewConsoleWriter(format Format, noColor bool, out io.Writer) *ConsoleWriter {
+	if out == nil {
+		_nilOutputWarning.Do(func() {
+			fmt.Fprintln(os.Stderr, "WARN   Console writer has no output, using STDOUT instead")
+		})
+		out = os.Stdout
+	}
+
 	w := ConsoleWriter{
 		format:  format,
 		noColor: noColor,
@@ -94,6 +660,62 @@ func NewConsoleWriter(format Format, noColor bool, out io.Writer) *ConsoleWriter
 	return &w
 }
 
+// NewConsoleWriterE creates a new ConsoleWriter similar to NewConsoleWriter. It returns an error if out is nil.
+func NewConsoleWriterE(format Format, noColor bool, out io.Writer) (*ConsoleWriter, error) {
+	if out == nil {
+		return nil, errors.New("Cannot create console writer, output is nil")
+	}
+	return NewConsoleWriter(format, noColor, out), nil
+}
+
+// SetLevelColors overrides the ANSI color codes (SGR parameters) of the levels in Default and Pretty format, for
+// example 33 for yellow or 7 for reversed. Levels absent from the map use their default color, while levels mapped to
+// zero are rendered without color. Colors are omitted entirely when color coding is disabled. Note that Default format
+// omits the label of Info logs.
+func SetLevelColors(colors map[Level]int) {
+	c := defaultLevelColors()
+	for l, color := range colors {
+		c[l] = color
+	}
+	_levelColors = c
+}
+
+// SetLevelLabels overrides the labels of the levels in Default and Pretty format, for example "WARNING" instead of
+// "WARN", or lowercase labels. Levels absent from the map use their default label, while a nil map restores all
+// defaults. The labels are padded to the length of the longest label. Note that Default format omits the label of Info
+// logs. The labels do not affect the level names in JSON and Logfmt format.
+func SetLevelLabels(labels map[Level]string) {
+	l := defaultLevelLabels()
+	for level, label := range labels {
+		l[level] = label
+	}
+	_levelLabels = l
+}
+
+// SetLevelWidth sets the minimum width of the level labels in Default and Pretty format, the default being 6. Labels
+// are padded with spaces to align the messages, while longer labels widen the column. A width of zero or less restores
+// the default.
+func SetLevelWidth(width int) {
+	if width <= 0 {
+		width = defaultLevelWidth
+	}
+	_levelWidth = width
+}
+
+// SetPrettySeparator sets the separator enclosing the level labels in Pretty format, such as "|" in
+// "10:00AM | INFO   | message", which is the default. An empty separator omits the separator altogether.
+func SetPrettySeparator(sep string) {
+	_prettySeparator = sep
+	_prettyLevelPattern = prettyLevelPattern(sep)
+}
+
+// SetRelativeTime instructs Pretty format to show the time elapsed since the start of the process instead of the wall
+// clock time, for example "+1.234s". The elapsed time is computed when the log is rendered. Relative timestamps do not
+// affect the other formats.
+func SetRelativeTime(relative bool) {
+	_relativeTime = relative
+}
+
 // SetFormatting updates the log format and color coding of an existing ConsoleWriter.
 func (w *ConsoleWriter) SetFormatting(f Format, noColor bool) {
 	if w.format != f || w.noColor != noColor {
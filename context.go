@@ -0,0 +1,68 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"context"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// contextKey defines the key type for storing fields in a context.Context.
+type contextKey struct{}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// ContextWith returns a copy of the context that stores the key/value pair as a field. Use WithContext to log messages
+// that include all fields stored in the context, for example to add a request ID to every log of an HTTP request.
+func ContextWith(ctx context.Context, key string, value interface{}) context.Context {
+	current := FromContext(ctx)
+	fields := make([]Field, 0, len(current)+1)
+	fields = append(fields, current...)
+	fields = append(fields, Field{Key: key, Value: value})
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+// FromContext retrieves the fields stored in the context by ContextWith. It returns nil if the context does not have
+// any fields.
+func FromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	if fields, ok := ctx.Value(contextKey{}).([]Field); ok {
+		return fields
+	}
+	return nil
+}
+
+// WithContext returns an Entry that adds the fields stored in the context to each message logged by the Logger
+// instance.
+func (l *Logger) WithContext(ctx context.Context) *Entry {
+	return newEntry(l, nil, FromContext(ctx)...)
+}
+
+// WithContext returns an Entry that adds the fields stored in the context to each message logged by the global logger.
+func WithContext(ctx context.Context) *Entry {
+	return newEntry(nil, nil, FromContext(ctx)...)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,68 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestWithContext(t *testing.T) {
+	w := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(JSON, true, w)
+
+	// store fields in the context and log through the context handle
+	ctx := ContextWith(context.Background(), "req_id", "abc123")
+	ctx = ContextWith(ctx, "attempt", 2)
+	require.Len(t, FromContext(ctx), 2)
+	WithContext(ctx).Info("handling request")
+	WithContext(ctx).With(Field{Key: "user", Value: "admin"}).Warnf("access by %s", "admin")
+
+	// log without context
+	Info("plain message")
+	WithContext(context.Background()).Info("empty context")
+
+	// test the fields are added in JSON format
+	got := w.Buffer()
+	require.Len(t, got, 4)
+	assert.Contains(t, got[0], `"req_id":"abc123","attempt":2`)
+	assert.Contains(t, got[1], `"req_id":"abc123","attempt":2,"user":"admin"`)
+	assert.Regexp(t, `^{"level":"info","time":"[^"]+","message":"plain message"}$`, got[2])
+	assert.Regexp(t, `^{"level":"info","time":"[^"]+","message":"empty context"}$`, got[3])
+
+	// test the fields are added in Pretty format
+	w.Reset()
+	SetFormatting(Pretty, true)
+	WithContext(ctx).Error("handling request")
+	got = w.Buffer()
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "| ERROR  | handling request attempt=2 req_id=abc123")
+
+	// test the instance handle
+	l := NewLogger(Default, true, w)
+	l.WithContext(ctx).Info("instance message")
+	assert.Contains(t, w.Buffer()[1], "instance message attempt=2 req_id=abc123")
+
+	// restore the logger settings
+	InitLogger(Default)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,136 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// CountingWriter implements a log writer that counts the logs per level before passing them to the wrapped writer, for
+// example to export the number of logs as metrics. A CountingWriter is safe for concurrent use.
+type CountingWriter struct {
+	writer Writer
+	counts map[Level]uint64
+	mu     sync.Mutex
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// count increments the counter of the level.
+func (w *CountingWriter) count(level Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.counts[level]++
+}
+
+// writeBypass counts the log as Info log and writes it to the wrapped writer in Default format, if supported.
+func (w *CountingWriter) writeBypass(p []byte) (n int, err error) {
+	w.count(InfoLevel)
+	return bypass(w.writer, p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewCountingWriter creates a log writer that counts the logs per level written to w. The level of each log is
+// determined before it is rendered, so the counts are accurate for all formats, including Default format that omits
+// the level of Info logs.
+func NewCountingWriter(w Writer) *CountingWriter {
+	return &CountingWriter{writer: w, counts: make(map[Level]uint64)}
+}
+
+// Close closes the wrapped writer if it implements io.Closer. The counts are retained.
+func (w *CountingWriter) Close() error {
+	if c, ok := w.writer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Counts returns a copy of the number of logs written per level since the creation of the writer or the most recent
+// call to Reset. Levels without any logs are absent.
+func (w *CountingWriter) Counts() map[Level]uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	counts := make(map[Level]uint64, len(w.counts))
+	for l, n := range w.counts {
+		counts[l] = n
+	}
+	return counts
+}
+
+// Flush flushes the wrapped writer, if it implements Flusher.
+func (w *CountingWriter) Flush() error {
+	return flush(w.writer)
+}
+
+// FlushContext flushes the wrapped writer until the context is done, see FlushWithTimeout.
+func (w *CountingWriter) FlushContext(ctx context.Context) error {
+	return flushWriter(ctx, w.writer)
+}
+
+// Reset sets the counts of all levels to zero.
+func (w *CountingWriter) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.counts = make(map[Level]uint64)
+}
+
+// SetFormatting updates the log format and color coding of the wrapped writer.
+func (w *CountingWriter) SetFormatting(format Format, noColor bool) {
+	w.writer.SetFormatting(format, noColor)
+}
+
+// Write implements the io.Writer interface for CountingWriter. It expects a single JSON-formatted log message. Logs
+// without a recognizable level are counted as NoLevel.
+func (w *CountingWriter) Write(p []byte) (n int, err error) {
+	level, ok := lineLevel(JSON, string(p))
+	if !ok {
+		level = NoLevel
+	}
+	w.count(level)
+	return w.writer.Write(p)
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for CountingWriter. It passes the level to the wrapped writer
+// if supported.
+func (w *CountingWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	w.count(Level(level))
+	if lw, ok := w.writer.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.writer.Write(p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,60 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestCountingWriter(t *testing.T) {
+	formats := []Format{Default, Pretty, JSON}
+	expected := map[Level]uint64{DebugLevel: 1, InfoLevel: 2, WarnLevel: 1, ErrorLevel: 2}
+	SetGlobalLevel(DebugLevel)
+	defer SetGlobalLevel(InfoLevel)
+
+	for _, format := range formats {
+		b := NewBufferedWriter(format, true)
+		w := NewCountingWriter(b)
+		l := NewLogger(format, true, w)
+
+		// test logs are counted per level and passed to the wrapped writer
+		l.Debug("Reading configuration")
+		l.Info("Listing snapshots")
+		l.Info("Listing volumes")
+		l.Warn("Snapshot is stale")
+		l.ErrorE(errors.New("not found"), "Cannot remove snapshot")
+		l.Errorf("Cannot remove volume %d", 42)
+		assert.Equal(t, expected, w.Counts(), format.String())
+		assert.Len(t, b.Buffer(), 6, format.String())
+
+		// test the counts are reset
+		w.Reset()
+		assert.Empty(t, w.Counts())
+	}
+
+	// test logs written directly are parsed
+	w := NewCountingWriter(NewBufferedWriter(JSON, true))
+	_, err := w.Write([]byte(`{"level":"warn","message":"Snapshot is stale"}` + "\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, map[Level]uint64{WarnLevel: 1}, w.Counts())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
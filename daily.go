@@ -0,0 +1,136 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// dailyLayout defines the layout of the date in the names of daily log files.
+const dailyLayout = "2006-01-02"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// dailyFileWriter implements a log writer that writes JSON-formatted logs to a file per day, such as
+// "app-2024-01-02.ndjson". It opens a new file when the date changes.
+type dailyFileWriter struct {
+	dir    string
+	prefix string
+	day    string
+	file   *os.File
+	now    func() time.Time // defines the clock, used for testing
+	mu     sync.Mutex
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// rotate opens the file of the current date if the date has changed since the previous write, closing the previous
+// file. A failure to close the previous file is reported to the write error handler, as the new file is ready to
+// receive logs. The caller must hold the lock.
+func (w *dailyFileWriter) rotate() error {
+	day := w.now().Format(dailyLayout)
+	if day == w.day && w.file != nil {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%s.ndjson", w.prefix, day)
+	f, err := os.OpenFile(filepath.Join(w.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("Cannot open log file '%s': %s", name, err)
+	}
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			handleWriteError(fmt.Errorf("Cannot close log file '%s-%s.ndjson': %s", w.prefix, w.day, err))
+		}
+	}
+	w.file = f
+	w.day = day
+	return nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewDailyFileWriter creates a log writer that writes logs to a file per day in dir, for example for archival. The
+// files are named after the prefix and the local date, such as "app-2024-01-02.ndjson", and contain a JSON-formatted
+// log per line. The date is checked on each write, as such a process running across midnight continues with a new
+// file. Logs are always written in JSON format, regardless of the format of the Logger. The directory is created if
+// needed, and files are opened in append mode. The writer is safe for concurrent use, and implements io.Closer to
+// close the current file.
+func NewDailyFileWriter(dir, prefix string) (Writer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("Cannot create log directory '%s': %s", dir, err)
+	}
+
+	w := &dailyFileWriter{dir: dir, prefix: prefix, now: time.Now}
+	if err := w.rotate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close closes the current file of the dailyFileWriter. A subsequent write opens the file again.
+func (w *dailyFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// SetFormatting is ignored by dailyFileWriter, as logs are always written in JSON format.
+func (w *dailyFileWriter) SetFormatting(format Format, noColor bool) {}
+
+// Write implements the io.Writer interface for dailyFileWriter. It writes the JSON-formatted log to the file of the
+// current date.
+func (w *dailyFileWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.rotate(); err != nil {
+		return 0, err
+	}
+	return w.file.Write(p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,87 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestDailyFileWriter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	w, err := NewDailyFileWriter(dir, "app")
+	require.Nil(t, err)
+	now := time.Date(2024, 1, 2, 23, 59, 59, 0, time.Local)
+	w.(*dailyFileWriter).now = func() time.Time { return now }
+	l := NewLogger(Pretty, true, w)
+	SetGlobalLevel(InfoLevel)
+
+	// test the logs are written in JSON format to the file of the day, across midnight
+	l.Info("Listing snapshots")
+	now = now.Add(2 * time.Second)
+	l.Warn("Snapshot missing")
+	read := func(name string) []string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		require.Nil(t, err)
+		return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	}
+	first := read("app-2024-01-02.ndjson")
+	require.Len(t, first, 1)
+	assert.Regexp(t, `^{"level":"info",.*"message":"Listing snapshots"}$`, first[0])
+	second := read("app-2024-01-03.ndjson")
+	require.Len(t, second, 1)
+	assert.Regexp(t, `^{"level":"warn",.*"message":"Snapshot missing"}$`, second[0])
+
+	// test the file of the current day is reopened after closing
+	require.Nil(t, w.(io.Closer).Close())
+	l.Info("Listing snapshots")
+	assert.Len(t, read("app-2024-01-03.ndjson"), 2)
+	require.Nil(t, w.(io.Closer).Close())
+}
+
+func TestDailyFileWriterCloseError(t *testing.T) {
+	var errs []error
+	SetWriteErrorHandler(func(err error) { errs = append(errs, err) })
+	defer SetWriteErrorHandler(nil)
+	dir := t.TempDir()
+	w, err := NewDailyFileWriter(dir, "app")
+	require.Nil(t, err)
+	defer w.(io.Closer).Close()
+	now := time.Date(2024, 1, 2, 23, 59, 59, 0, time.Local)
+	w.(*dailyFileWriter).now = func() time.Time { return now }
+
+	// test a failure to close the file of the previous day is reported, while the log is still written
+	require.Nil(t, w.(*dailyFileWriter).file.Close())
+	now = now.Add(2 * time.Second)
+	_, err = w.Write([]byte(`{"message":"Snapshot missing"}` + "\n"))
+	require.Nil(t, err)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "Cannot close log file 'app-")
+	b, err := os.ReadFile(filepath.Join(dir, "app-2024-01-03.ndjson"))
+	require.Nil(t, err)
+	assert.Contains(t, string(b), "Snapshot missing")
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,205 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// dedupWriter implements a log writer that collapses identical consecutive logs. The first log is written as-is, while
+// subsequent identical logs are counted. The count is written as a single log once a different log arrives, the
+// window elapses, or the writer is closed.
+type dedupWriter struct {
+	writer  Writer
+	window  time.Duration
+	mu      sync.Mutex
+	key     string
+	last    map[string]interface{}
+	level   zerolog.Level // level of the collapsed logs
+	count   int
+	timer   *time.Timer
+	stopped bool
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// dedupKey returns a key identifying the log, ignoring its timestamp. Fields are sorted by name, as the keys of a map
+// are sorted when marshaled to JSON.
+func dedupKey(evt map[string]interface{}) string {
+	ts, ok := evt[zerolog.TimestampFieldName]
+	delete(evt, zerolog.TimestampFieldName)
+	b, _ := json.Marshal(evt)
+	if ok {
+		evt[zerolog.TimestampFieldName] = ts
+	}
+	return string(b)
+}
+
+// flushPending writes the number of collapsed logs, if any, using the most recent duplicate as template. The caller
+// must hold the lock.
+func (w *dedupWriter) flushPending() error {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if w.count == 0 {
+		return nil
+	}
+
+	evt := w.last
+	evt[zerolog.MessageFieldName] = fmt.Sprintf("%v (repeated %d times)", evt[zerolog.MessageFieldName], w.count)
+	w.count = 0
+	b, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, err = writeLevel(w.writer, w.level, append(b, '\n'))
+	return err
+}
+
+// writeBypass writes the pending count followed by the log to the wrapped writer in Default format, if supported.
+// Logs written by Bypass are never collapsed.
+func (w *dedupWriter) writeBypass(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.flushPending(); err != nil {
+		return 0, err
+	}
+	w.key = ""
+	return bypass(w.writer, p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewDedupWriter creates a log writer that collapses identical consecutive logs written to w. Logs are considered
+// identical when all their fields, except for the timestamp, are equal. The first log is written immediately. Any
+// subsequent duplicates are counted and written as a single log with the suffix "(repeated x times)", as soon as a
+// different log arrives or the window elapses. Close writes the pending count.
+func NewDedupWriter(w Writer, window time.Duration) Writer {
+	return &dedupWriter{writer: w, window: window}
+}
+
+// Close writes the pending count of collapsed logs, if any. It closes the wrapped writer if it implements io.Closer.
+func (w *dedupWriter) Close() error {
+	w.mu.Lock()
+	err := w.flushPending()
+	w.stopped = true
+	w.mu.Unlock()
+
+	if c, ok := w.writer.(io.Closer); ok {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Flush writes the pending count of collapsed logs, if any, and flushes the wrapped writer if it implements Flusher.
+func (w *dedupWriter) Flush() error {
+	w.mu.Lock()
+	err := w.flushPending()
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return flush(w.writer)
+}
+
+// FlushContext writes the pending count of collapsed logs, if any, and flushes the wrapped writer until the context is
+// done, see FlushWithTimeout.
+func (w *dedupWriter) FlushContext(ctx context.Context) error {
+	w.mu.Lock()
+	err := w.flushPending()
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return flushWriter(ctx, w.writer)
+}
+
+// SetFormatting updates the log format and color coding of the wrapped writer.
+func (w *dedupWriter) SetFormatting(format Format, noColor bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writer.SetFormatting(format, noColor)
+}
+
+// Write implements the io.Writer interface for dedupWriter. It expects a single JSON-formatted log message.
+func (w *dedupWriter) Write(p []byte) (n int, err error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for dedupWriter. It passes the level to the wrapped writer
+// if supported, including the level of the log announcing the number of collapsed logs.
+func (w *dedupWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	var evt map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	if err := d.Decode(&evt); err != nil {
+		return 0, fmt.Errorf("cannot decode event: %s", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// count the log if it is identical to the previous log
+	key := dedupKey(evt)
+	if key == w.key && !w.stopped {
+		w.count++
+		w.last = evt
+		w.level = level
+		if w.timer == nil && w.window > 0 {
+			w.timer = time.AfterFunc(w.window, func() {
+				w.mu.Lock()
+				defer w.mu.Unlock()
+				w.flushPending() //nolint:errcheck // errors cannot be reported back to the caller
+			})
+		}
+		return len(p), nil
+	}
+
+	// write the pending count followed by the new log
+	if err := w.flushPending(); err != nil {
+		return 0, err
+	}
+	w.key = key
+	return writeLevel(w.writer, level, p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,123 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+// syncWriter defines a buffered writer that is safe for concurrent use, used to test writers with background
+// goroutines.
+type syncWriter struct {
+	mu     sync.Mutex
+	writer *BufferedWriter
+}
+
+func (w *syncWriter) Buffer() Buffer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append(Buffer{}, w.writer.Buffer()...)
+}
+
+func (w *syncWriter) SetFormatting(format Format, noColor bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writer.SetFormatting(format, noColor)
+}
+
+func (w *syncWriter) writeBypass(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writer.writeBypass(p)
+}
+
+func (w *syncWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writer.Write(p)
+}
+
+func TestDedupWriter(t *testing.T) {
+	b := &syncWriter{writer: NewBufferedWriter(Default, true)}
+	w := NewDedupWriter(b, time.Hour)
+	l := NewLogger(Pretty, true, w)
+	SetGlobalLevel(InfoLevel)
+
+	// test identical messages collapse until a different message arrives
+	for i := 0; i < 4; i++ {
+		l.Info("still waiting...")
+	}
+	require.Len(t, b.Buffer(), 1)
+	l.Info("done")
+	got := b.Buffer()
+	require.Len(t, got, 3)
+	assert.Contains(t, got[0], "| INFO   | still waiting...")
+	assert.Contains(t, got[1], "| INFO   | still waiting... (repeated 3 times)")
+	assert.Contains(t, got[2], "| INFO   | done")
+
+	// test messages with different levels are not collapsed
+	l.Warn("done")
+	assert.Len(t, b.Buffer(), 4)
+
+	// test the pending count is written on close
+	l.Warn("done")
+	l.Warn("done")
+	assert.Len(t, b.Buffer(), 4)
+	require.Nil(t, w.(io.Closer).Close())
+	got = b.Buffer()
+	require.Len(t, got, 5)
+	assert.Contains(t, got[4], "| WARN   | done (repeated 2 times)")
+}
+
+func TestDedupWriterTimeout(t *testing.T) {
+	b := &syncWriter{writer: NewBufferedWriter(Default, true)}
+	w := NewDedupWriter(b, 10*time.Millisecond)
+	l := NewLogger(Default, true, w)
+	SetGlobalLevel(InfoLevel)
+
+	// test the pending count is written once the window elapses
+	l.Info("still waiting...")
+	l.Info("still waiting...")
+	require.Eventually(t, func() bool { return len(b.Buffer()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Contains(t, b.Buffer()[1], "still waiting... (repeated 1 times)")
+	require.Nil(t, w.(io.Closer).Close())
+	assert.Len(t, b.Buffer(), 2)
+}
+
+func TestDedupWriterLevel(t *testing.T) {
+	lw := &levelWriter{}
+	w := NewDedupWriter(lw, 0)
+	l := NewLogger(JSON, true, w)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test the level is passed to the wrapped writer, including the level of the collapsed logs
+	l.Error("Cannot connect")
+	l.Error("Cannot connect")
+	l.Warn("Snapshot missing")
+	assert.Equal(t, []zerolog.Level{zerolog.ErrorLevel, zerolog.ErrorLevel, zerolog.WarnLevel}, lw.levels)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,184 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// componentFieldName defines the name of the field that holds the component set by Named.
+const componentFieldName = "component"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// Entry defines a logging handle that adds structured fields to each logged message. An Entry is bound to either a
+// Logger instance or the global logger. In the latter case, the Entry follows any changes made to the global logger by
+// InitLogger and related functions.
+type Entry struct {
+	logger *Logger
+	fields []Field
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// newEntry creates a new Entry for the Logger, combining the current and additional fields. A nil Logger refers to the
+// global logger.
+func newEntry(l *Logger, current []Field, fields ...Field) *Entry {
+	e := Entry{logger: l}
+	e.fields = make([]Field, 0, len(current)+len(fields))
+	e.fields = append(e.fields, current...)
+	e.fields = append(e.fields, fields...)
+	return &e
+}
+
+// target returns the Logger the Entry is bound to.
+func (e *Entry) target() *Logger {
+	if e.logger != nil {
+		return e.logger
+	}
+	return _logger
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// Debug logs a debugging message with the fields of the Entry.
+func (e *Entry) Debug(msg string) {
+	e.target().log(DebugLevel, e.fields, msg, nil)
+}
+
+// DebugE logs a debugging error with the fields of the Entry.
+func (e *Entry) DebugE(err error, msg string) {
+	e.target().log(DebugLevel, e.fields, msg, err)
+}
+
+// Debugf logs a formatted debugging message with the fields of the Entry.
+func (e *Entry) Debugf(format string, v ...interface{}) {
+	e.target().log(DebugLevel, e.fields, format, nil, v...)
+}
+
+// Error logs an error message with the fields of the Entry.
+func (e *Entry) Error(msg string) {
+	e.target().log(ErrorLevel, e.fields, msg, nil)
+}
+
+// ErrorE logs an error with the fields of the Entry.
+func (e *Entry) ErrorE(err error, msg string) {
+	e.target().log(ErrorLevel, e.fields, msg, err)
+}
+
+// Errorf logs a formatted error message with the fields of the Entry.
+func (e *Entry) Errorf(format string, v ...interface{}) {
+	e.target().log(ErrorLevel, e.fields, format, nil, v...)
+}
+
+// Info logs a message with the fields of the Entry.
+func (e *Entry) Info(msg string) {
+	e.target().log(InfoLevel, e.fields, msg, nil)
+}
+
+// InfoE logs an error with the fields of the Entry.
+func (e *Entry) InfoE(err error, msg string) {
+	e.target().log(InfoLevel, e.fields, msg, err)
+}
+
+// Infof logs a formatted message with the fields of the Entry.
+func (e *Entry) Infof(format string, v ...interface{}) {
+	e.target().log(InfoLevel, e.fields, format, nil, v...)
+}
+
+// Msg logs a message at the desired level with the fields of the Entry.
+func (e *Entry) Msg(level Level, msg string) {
+	e.target().log(level, e.fields, msg, nil)
+}
+
+// MsgE logs an error at the desired level with the fields of the Entry.
+func (e *Entry) MsgE(level Level, err error, msg string) {
+	e.target().log(level, e.fields, msg, err)
+}
+
+// Msgf logs a formatted message at the desired level with the fields of the Entry.
+func (e *Entry) Msgf(level Level, format string, v ...interface{}) {
+	e.target().log(level, e.fields, format, nil, v...)
+}
+
+// Trace logs a tracing message with the fields of the Entry.
+func (e *Entry) Trace(msg string) {
+	e.target().log(TraceLevel, e.fields, msg, nil)
+}
+
+// TraceE logs a tracing error with the fields of the Entry.
+func (e *Entry) TraceE(err error, msg string) {
+	e.target().log(TraceLevel, e.fields, msg, err)
+}
+
+// Tracef logs a formatted tracing message with the fields of the Entry.
+func (e *Entry) Tracef(format string, v ...interface{}) {
+	e.target().log(TraceLevel, e.fields, format, nil, v...)
+}
+
+// Warn logs a warning with the fields of the Entry.
+func (e *Entry) Warn(msg string) {
+	e.target().log(WarnLevel, e.fields, msg, nil)
+}
+
+// WarnE logs an error as warning with the fields of the Entry.
+func (e *Entry) WarnE(err error, msg string) {
+	e.target().log(WarnLevel, e.fields, msg, err)
+}
+
+// Warnf logs a formatted warning with the fields of the Entry.
+func (e *Entry) Warnf(format string, v ...interface{}) {
+	e.target().log(WarnLevel, e.fields, format, nil, v...)
+}
+
+// Named returns an Entry that tags each message logged by the Logger instance with the name of a component, see Named.
+func (l *Logger) Named(component string) *Entry {
+	return newEntry(l, nil, Field{Key: componentFieldName, Value: component})
+}
+
+// Named returns an Entry that tags each message logged by the global logger with the name of a component, such as a
+// subsystem of the application. The component is rendered as field in JSON and Logfmt format, such as
+// "component":"billing", as bracketed prefix of the message in Pretty format, such as "[billing] Created invoice", and
+// as prefix of the message in Default format, such as "billing: Created invoice". The Entry shares the writers and
+// level of the global logger. Use With to add further fields.
+func Named(component string) *Entry {
+	return newEntry(nil, nil, Field{Key: componentFieldName, Value: component})
+}
+
+// With returns a new Entry that adds the provided fields to the fields of the current Entry.
+func (e *Entry) With(fields ...Field) *Entry {
+	return newEntry(e.logger, e.fields, fields...)
+}
+
+// With returns an Entry that adds the provided fields to each message logged by the Logger instance.
+func (l *Logger) With(fields ...Field) *Entry {
+	return newEntry(l, nil, fields...)
+}
+
+// With returns an Entry that adds the provided fields to each message logged by the global logger.
+func With(fields ...Field) *Entry {
+	return newEntry(nil, nil, fields...)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,92 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// Defines the environment variables read by InitFromEnv.
+const (
+	envDeferInit  = "LOG_DEFER_INIT"
+	envFormat     = "LOG_FORMAT"
+	envLevel      = "LOG_LEVEL"
+	envLogNoColor = "LOG_NO_COLOR"
+	envNoColor    = "NO_COLOR"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// InitFromEnv configures the global logger using environment variables. LOG_FORMAT sets the format (see ParseFormat)
+// and defaults to Default. LOG_LEVEL sets the global level (see ParseLevel) and defaults to InfoLevel. Color coding is
+// enabled unless LOG_NO_COLOR is set to a true value, such as "1" or "true", or unless NO_COLOR is set to any non-empty
+// value (see https://no-color.org). The existing writers are preserved. Invalid values are replaced by their defaults,
+// in which case InitFromEnv returns an error describing each invalid value. If LOG_DEFER_INIT is set, any logs held
+// since the start of the program are written once the settings are applied.
+func InitFromEnv() error {
+	var errs multiError
+
+	format := Default
+	if v := os.Getenv(envFormat); v != "" {
+		f, err := ParseFormat(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Cannot parse %s: %s", envFormat, err))
+		} else {
+			format = f
+		}
+	}
+
+	level := InfoLevel
+	if v := os.Getenv(envLevel); v != "" {
+		l, err := ParseLevel(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Cannot parse %s: %s", envLevel, err))
+		} else {
+			level = l
+		}
+	}
+
+	noColor := os.Getenv(envNoColor) != ""
+	if v := os.Getenv(envLogNoColor); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Cannot parse %s: invalid boolean '%s'", envLogNoColor, v))
+		} else {
+			noColor = noColor || b
+		}
+	}
+
+	SetFormatting(format, noColor)
+	SetGlobalLevel(level)
+	releaseInit()
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,124 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestInitFromEnv(t *testing.T) {
+	type test struct {
+		name    string
+		env     map[string]string
+		format  Format
+		level   Level
+		noColor bool
+		err     string
+	}
+	var tests = []test{
+		{name: "defaults", env: map[string]string{}, format: Default, level: InfoLevel, noColor: false},
+		{name: "valid values", env: map[string]string{"LOG_FORMAT": "json", "LOG_LEVEL": "debug",
+			"LOG_NO_COLOR": "true"}, format: JSON, level: DebugLevel, noColor: true},
+		{name: "NO_COLOR", env: map[string]string{"LOG_FORMAT": "Pretty", "NO_COLOR": "1"}, format: Pretty,
+			level: InfoLevel, noColor: true},
+		{name: "NO_COLOR precedence", env: map[string]string{"NO_COLOR": "1", "LOG_NO_COLOR": "false"},
+			format: Default, level: InfoLevel, noColor: true},
+		{name: "invalid values", env: map[string]string{"LOG_FORMAT": "xml", "LOG_LEVEL": "loud",
+			"LOG_NO_COLOR": "maybe"}, format: Default, level: InfoLevel, noColor: false,
+			err: "Cannot parse LOG_FORMAT: unknown log format: 'xml'; Cannot parse LOG_LEVEL: Unknown Level String: " +
+				"'loud', defaulting to NoLevel; Cannot parse LOG_NO_COLOR: invalid boolean 'maybe'"},
+	}
+
+	vars := []string{"LOG_FORMAT", "LOG_LEVEL", "LOG_NO_COLOR", "NO_COLOR"}
+	for _, v := range vars {
+		if old, ok := os.LookupEnv(v); ok {
+			defer os.Setenv(v, old)
+		} else {
+			defer os.Unsetenv(v)
+		}
+	}
+
+	for _, test := range tests {
+		for _, v := range vars {
+			os.Unsetenv(v)
+		}
+		for k, v := range test.env {
+			os.Setenv(k, v)
+		}
+		SetGlobalLevel(WarnLevel)
+
+		err := InitFromEnv()
+		if test.err != "" {
+			require.NotNil(t, err, test.name)
+			assert.Equal(t, test.err, err.Error(), test.name)
+		} else {
+			assert.Nil(t, err, test.name)
+		}
+		assert.Equal(t, test.format, CurrentFormat(), test.name)
+		assert.Equal(t, test.level, GlobalLevel(), test.name)
+		assert.Equal(t, test.noColor, NoColor(), test.name)
+	}
+
+	InitLogger(Default)
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestDeferInit(t *testing.T) {
+	defer os.Unsetenv("LOG_DEFER_INIT")
+	os.Setenv("LOG_DEFER_INIT", "true")
+
+	// test logs written before initialization are held, also when writers are added
+	_logger = newDefaultLogger()
+	SetGlobalLevel(InfoLevel)
+	Info("Loading plugins")
+	early := NewBufferedWriter(Default, true)
+	AppendWriter(early)
+	Warn("Plugin is deprecated")
+	assert.Empty(t, early.Buffer())
+	assert.Equal(t, 2, HeldCount())
+
+	// test the held logs appear on the configured writer after initialization
+	w := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(JSON, true, w)
+	Info("Initialized")
+	require.Len(t, w.Buffer(), 3)
+	assert.Contains(t, w.Buffer()[0], `"message":"Loading plugins"`)
+	assert.Contains(t, w.Buffer()[1], `"message":"Plugin is deprecated"`)
+	assert.Contains(t, w.Buffer()[2], `"message":"Initialized"`)
+
+	// test InitFromEnv releases the held logs too
+	_logger = newDefaultLogger()
+	Info("Loading plugins")
+	w = NewBufferedWriter(Default, true)
+	SetWriters(w)
+	assert.Empty(t, w.Buffer())
+	require.Nil(t, InitFromEnv())
+	assert.Equal(t, Buffer{"Loading plugins"}, w.Buffer())
+
+	// test logs are not held without the environment variable
+	os.Unsetenv("LOG_DEFER_INIT")
+	_logger = newDefaultLogger()
+	assert.False(t, _initPending)
+	InitLogger(Default)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
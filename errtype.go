@@ -0,0 +1,41 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// errorTypeFieldName defines the name of the field containing the dynamic type of an error.
+const errorTypeFieldName = "error_type"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _errorType indicates whether the dynamic type of errors is logged.
+var _errorType bool
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetErrorTypeField enables or disables logging the dynamic type of errors. When enabled, logs of non-nil errors
+// include an "error_type" field with the Go type name of the error, such as "*fs.PathError", to group errors in a log
+// aggregator. Default and Pretty format render the field as error_type=*fs.PathError.
+func SetErrorTypeField(enabled bool) {
+	_errorType = enabled
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
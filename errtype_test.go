@@ -0,0 +1,69 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSetErrorTypeField(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	_, err := os.Open("/nonexistent")
+	require.Error(t, err)
+
+	// test the type is omitted when disabled
+	ErrorE(err, "Cannot open file")
+	require.Len(t, w.Buffer(), 1)
+	assert.NotContains(t, w.Buffer()[0], "error_type")
+
+	// test the type of a typed error is logged when enabled
+	SetErrorTypeField(true)
+	defer SetErrorTypeField(false)
+	WarnE(err, "Cannot open file")
+	require.Len(t, w.Buffer(), 2)
+	assert.Contains(t, w.Buffer()[1], "error_type=*fs.PathError")
+
+	// test nil errors add nothing
+	ErrorE(nil, "Cannot open file")
+	require.Len(t, w.Buffer(), 3)
+	assert.Equal(t, "ERROR  Cannot open file", w.Buffer()[2])
+
+	// test the type in JSON format
+	w.Reset()
+	SetFormatting(JSON, true)
+	ErrorE(err, "Cannot open file")
+	require.Len(t, w.Buffer(), 1)
+	assert.Contains(t, w.Buffer()[0], `"error_type":"*fs.PathError"`)
+
+	// test the type of a fatal error
+	w.Reset()
+	_suppressExit = true
+	defer func() { _suppressExit = false }()
+	FatalE(err, "Cannot open file")
+	require.Len(t, w.Buffer(), 1)
+	assert.Contains(t, w.Buffer()[0], `"level":"fatal"`)
+	assert.Contains(t, w.Buffer()[0], `"error_type":"*fs.PathError"`)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
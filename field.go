@@ -0,0 +1,243 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// missingValue defines the value of a trailing key without value, see InfoKV.
+const missingValue = "!MISSING-VALUE"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _floatPrecision defines the number of decimals of floating-point fields, a negative value uses the smallest number
+// of decimals needed to represent the value exactly.
+var _floatPrecision = -1
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// Field defines a structured key/value pair to be added to a log message. Fields are rendered as attributes in JSON
+// format and as key=value pairs in the other formats.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// fieldGroup defines a group of fields that is rendered as nested object, see Dict.
+type fieldGroup []Field
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// appendFields adds the fields to a zerolog event in the order provided.
+func appendFields(e *zerolog.Event, fields []Field) *zerolog.Event {
+	for _, f := range fields {
+		e = f.append(e)
+	}
+	return e
+}
+
+// append adds the field to a zerolog event, using a typed function for common types to avoid reflection.
+func (f Field) append(e *zerolog.Event) *zerolog.Event {
+	switch v := f.Value.(type) {
+	case fieldGroup:
+		if len(v) == 0 {
+			return e
+		}
+		return e.Dict(f.Key, appendFields(zerolog.Dict(), v))
+	case string:
+		return e.Str(f.Key, v)
+	case []string:
+		if v == nil {
+			return e
+		}
+		return e.Strs(f.Key, v)
+	case int:
+		return e.Int(f.Key, v)
+	case int64:
+		return e.Int64(f.Key, v)
+	case byteSize:
+		return e.Int64(f.Key, int64(v))
+	case uint64:
+		return e.Uint64(f.Key, v)
+	case float64:
+		if _floatPrecision < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return e.Float64(f.Key, v)
+		}
+		return e.RawJSON(f.Key, strconv.AppendFloat(nil, v, 'f', _floatPrecision, 64))
+	case bool:
+		return e.Bool(f.Key, v)
+	case error:
+		return e.AnErr(f.Key, v)
+	case time.Time:
+		return e.Time(f.Key, v)
+	case time.Duration:
+		return e.Dur(f.Key, v)
+	default:
+		if isNilCollection(v) {
+			return e
+		}
+		return e.Interface(f.Key, v)
+	}
+}
+
+// keyvalFields converts alternating key/value pairs into fields. Keys that are not strings are converted using
+// fmt.Sprint, while a trailing key without value gets the value missingValue.
+func keyvalFields(keyvals []interface{}) []Field {
+	if len(keyvals) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		var value interface{} = missingValue
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+	return fields
+}
+
+// isNilCollection returns true if the value is a nil map or a nil slice.
+func isNilCollection(v interface{}) bool {
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Map, reflect.Slice:
+		return r.IsNil()
+	default:
+		return false
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// Any returns a field for a value of any type, such as a map or a struct. The value is marshaled to JSON using
+// reflection, maps are rendered as JSON object and slices as JSON array. Default and Pretty format render maps as
+// bracketed group, such as meta=[env=prod region=eu], and slices as bracketed list, such as tags=[web api]. Nil maps
+// and nil slices are omitted.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns a field for a boolean value, rendered as key=true in Default and Pretty format.
+func Bool(key string, b bool) Field {
+	return Field{Key: key, Value: b}
+}
+
+// Dict returns a field that groups the provided fields under a single key, for example to add the details of an HTTP
+// request. The group is rendered as nested object in JSON format, such as {"http":{"method":"GET","status":200}}, and
+// as bracketed group in Default and Pretty format, such as http=[method=GET status=200]. Logfmt format joins the keys
+// with a dot, such as http.method=GET. Groups can be nested, while empty groups are omitted.
+func Dict(key string, fields ...Field) Field {
+	return Field{Key: key, Value: fieldGroup(fields)}
+}
+
+// Fields converts a map into a list of fields, sorted by key, for example to add a map of metadata as separate
+// fields using With(Fields(m)...). A nil map results in an empty list.
+func Fields(m map[string]interface{}) []Field {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, Field{Key: k, Value: m[k]})
+	}
+	return fields
+}
+
+// Float64 returns a field for a floating-point value. The value is rendered without scientific notation, such as
+// key=3.14 in Default and Pretty format, using the precision set by SetFloatPrecision. As JSON does not support NaN and
+// infinite values, these values are rendered as the strings "NaN", "+Inf", and "-Inf" instead.
+func Float64(key string, f float64) Field {
+	return Field{Key: key, Value: f}
+}
+
+// Int64 returns a field for a signed integer value.
+func Int64(key string, i int64) Field {
+	return Field{Key: key, Value: i}
+}
+
+// SetFloatPrecision sets the number of decimals of floating-point fields, for example 2 to render 3.14159 as 3.14. A
+// negative precision restores the default, which uses the smallest number of decimals needed to represent the value
+// exactly.
+func SetFloatPrecision(precision int) {
+	if precision < 0 {
+		precision = -1
+	}
+	_floatPrecision = precision
+}
+
+// Strs returns a field for a list of strings, such as tags. The list is rendered as JSON array in JSON format and as
+// bracketed list in Default and Pretty format, such as tags=[web api]. A nil list is omitted.
+func Strs(key string, vals []string) Field {
+	return Field{Key: key, Value: vals}
+}
+
+// Uint64 returns a field for an unsigned integer value.
+func Uint64(key string, i uint64) Field {
+	return Field{Key: key, Value: i}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
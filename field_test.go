@@ -0,0 +1,153 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestDict(t *testing.T) {
+	w := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(JSON, true, w)
+	SetGlobalLevel(InfoLevel)
+	entry := With(
+		Field{Key: "service", Value: "api"},
+		Dict("http", Field{Key: "method", Value: "GET"}, Field{Key: "status", Value: 200},
+			Dict("client", Field{Key: "ip", Value: "10.0.0.1"})),
+		Dict("empty"),
+	)
+
+	// test the nested JSON structure
+	entry.Info("Request handled")
+	require.Len(t, w.Buffer(), 1)
+	var evt map[string]interface{}
+	require.Nil(t, json.Unmarshal([]byte(w.Buffer()[0]), &evt))
+	assert.Equal(t, "api", evt["service"])
+	assert.Equal(t, map[string]interface{}{
+		"method": "GET",
+		"status": float64(200),
+		"client": map[string]interface{}{"ip": "10.0.0.1"},
+	}, evt["http"])
+	assert.NotContains(t, evt, "empty")
+
+	// test the group is rendered in the other formats
+	type test struct {
+		format   Format
+		expected string
+	}
+	var tests = []test{
+		{format: Default, expected: "Request handled http=[client=[ip=10.0.0.1] method=GET status=200] service=api"},
+		{format: Pretty, expected: "| INFO   | Request handled http=[client=[ip=10.0.0.1] method=GET status=200] " +
+			"service=api"},
+		{format: Logfmt, expected: "http.client.ip=10.0.0.1 http.method=GET http.status=200 service=api"},
+	}
+	for _, test := range tests {
+		w.Reset()
+		SetFormatting(test.format, true)
+		entry.Info("Request handled")
+		require.Len(t, w.Buffer(), 1, test.format.String())
+		assert.Contains(t, w.Buffer()[0], test.expected, test.format.String())
+	}
+
+	InitLogger(Default)
+}
+
+func TestCollectionFields(t *testing.T) {
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	var nilMap map[string]string
+	var nilSlice []int
+	meta := map[string]interface{}{"region": "eu west", "env": "prod"}
+	fields := append(Fields(meta), Any("limits", map[string]int{"cpu": 2}), Strs("tags", []string{"web", "api"}),
+		Any("ports", []int{80, 443}), Any("empty", nilMap), Any("none", nilSlice), Strs("no tags", nil))
+	assert.Len(t, Fields(nil), 0)
+
+	// test maps render as JSON object and slices as JSON array
+	w := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, w)
+	l.With(fields...).Info("Started")
+	assert.Equal(t, Buffer{`{"level":"info","env":"prod","region":"eu west","limits":{"cpu":2},"tags":["web","api"],` +
+		`"ports":[80,443],"message":"Started"}`}, w.Buffer())
+
+	// test maps and slices render inline in Default format
+	w = NewBufferedWriter(Default, true)
+	l = NewLogger(Default, true, w)
+	l.With(fields...).Info("Started")
+	assert.Equal(t, Buffer{`Started env=prod limits=[cpu=2] ports=[80 443] region="eu west" tags=[web api]`},
+		w.Buffer())
+}
+
+func TestTypedFields(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	entry := With(Bool("ok", true), Float64("ratio", 3.14), Float64("large", 1e21), Int64("offset", -42),
+		Uint64("size", 18446744073709551615))
+
+	// test the values are rendered in each format
+	type test struct {
+		format   Format
+		expected string
+	}
+	var tests = []test{
+		{format: JSON, expected: `{"level":"info","ok":true,"ratio":3.14,"large":1000000000000000000000,` +
+			`"offset":-42,"size":18446744073709551615,"message":"Stats"}`},
+		{format: Default, expected: "Stats large=1000000000000000000000 offset=-42 ok=true ratio=3.14 " +
+			"size=18446744073709551615"},
+		{format: Pretty, expected: "| INFO   | Stats large=1000000000000000000000 offset=-42 ok=true ratio=3.14 " +
+			"size=18446744073709551615"},
+	}
+	for _, test := range tests {
+		w.Reset()
+		SetFormatting(test.format, true)
+		entry.Info("Stats")
+		require.Len(t, w.Buffer(), 1, test.format.String())
+		assert.Contains(t, w.Buffer()[0], test.expected, test.format.String())
+	}
+
+	// test NaN and infinite values fall back to strings
+	w.Reset()
+	SetFormatting(JSON, true)
+	With(Float64("nan", math.NaN()), Float64("inf", math.Inf(1)), Float64("neg", math.Inf(-1))).Info("Stats")
+	assert.Equal(t, Buffer{`{"level":"info","nan":"NaN","inf":"+Inf","neg":"-Inf","message":"Stats"}`}, w.Buffer())
+	w.Reset()
+	SetFormatting(Default, true)
+	With(Float64("nan", math.NaN()), Float64("inf", math.Inf(1))).Info("Stats")
+	assert.Equal(t, Buffer{"Stats inf=+Inf nan=NaN"}, w.Buffer())
+
+	// test the precision
+	SetFloatPrecision(2)
+	defer SetFloatPrecision(-1)
+	w.Reset()
+	SetFormatting(JSON, true)
+	With(Float64("ratio", 3.14159), Float64("nan", math.NaN())).Info("Stats")
+	assert.Equal(t, Buffer{`{"level":"info","ratio":3.14,"nan":"NaN","message":"Stats"}`}, w.Buffer())
+	w.Reset()
+	SetFormatting(Pretty, true)
+	With(Float64("ratio", 2)).Info("Stats")
+	require.Len(t, w.Buffer(), 1)
+	assert.Contains(t, w.Buffer()[0], "Stats ratio=2.00")
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
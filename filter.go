@@ -0,0 +1,114 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _dropEvents defines the keys of fields that suppress the entire log, see DropEventsWithField.
+var _dropEvents = map[string]bool{}
+
+// _dropFields defines the keys of fields that are removed from each log, see DropField.
+var _dropFields = map[string]bool{}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// filterFields removes the fields registered by DropField from the fields, including fields nested in a group. It
+// returns false if the log should be suppressed, as it contains a field registered by DropEventsWithField. The input
+// is returned as-is if no filters are registered.
+func filterFields(fields []Field) ([]Field, bool) {
+	if len(fields) == 0 || (len(_dropEvents) == 0 && len(_dropFields) == 0) {
+		return fields, true
+	}
+
+	filtered := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if _dropEvents[f.Key] {
+			return nil, false
+		}
+		if _dropFields[f.Key] {
+			continue
+		}
+		switch v := f.Value.(type) {
+		case fieldGroup:
+			nested, ok := filterFields(v)
+			if !ok {
+				return nil, false
+			}
+			f = Field{Key: f.Key, Value: fieldGroup(nested)}
+		case map[string]interface{}:
+			nested, ok := filterMap(v)
+			if !ok {
+				return nil, false
+			}
+			f = Field{Key: f.Key, Value: nested}
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered, true
+}
+
+// filterMap removes the keys registered by DropField from the map, including keys of nested maps, similar to
+// filterFields. It returns false if the map contains a key registered by DropEventsWithField. The map itself is not
+// modified, instead a filtered copy is returned.
+func filterMap(m map[string]interface{}) (map[string]interface{}, bool) {
+	if len(m) == 0 || (len(_dropEvents) == 0 && len(_dropFields) == 0) {
+		return m, true
+	}
+
+	filtered := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if _dropEvents[k] {
+			return nil, false
+		}
+		if _dropFields[k] {
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			if v, ok = filterMap(nested); !ok {
+				return nil, false
+			}
+		}
+		filtered[k] = v
+	}
+	return filtered, true
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// DropEventsWithField suppresses any log that contains a field with the given key, regardless of its value, for
+// example to never emit logs that carry personal data. The filter applies to the fields of all loggers, including
+// fields nested by Dict or Any(map), base fields, and fields added by hooks. If a base field matches, the global logger
+// suppresses all logs. Held messages are filtered when they are flushed. DropEventsWithField is not safe for concurrent
+// use with logging, register any filters during initialization instead.
+func DropEventsWithField(key string) {
+	_dropEvents[key] = true
+	_logger.initHandler()
+}
+
+// DropField removes any field with the given key from each log, while the log itself is still written. Similar to
+// DropEventsWithField, the filter applies to the fields of all loggers, including fields nested by Dict or Any(map),
+// base fields, and fields added by hooks. DropField is not safe for concurrent use with logging, register any filters
+// during initialization instead.
+func DropField(key string) {
+	_dropFields[key] = true
+	_logger.initHandler()
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,136 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestDropEventsWithField(t *testing.T) {
+	w := newTestLogger(t, Logfmt)
+	SetGlobalLevel(InfoLevel)
+	defer func() {
+		_dropEvents = map[string]bool{}
+		_hooks = nil
+	}()
+
+	// test logs with a matching field are suppressed, including nested fields
+	DropEventsWithField("ssn")
+	With(Field{Key: "ssn", Value: "123-45-6789"}).Info("Created user")
+	With(Dict("user", Field{Key: "ssn", Value: 123456789})).Info("Created user")
+	With(Field{Key: "id", Value: 42}).Info("Created account")
+	assert.Len(t, w.Buffer(), 1)
+	assert.Contains(t, strings.Join(w.Buffer(), "\n"), "id=42")
+
+	// test held messages are filtered at flush time
+	w.Reset()
+	Hold()
+	With(Field{Key: "ssn", Value: "123-45-6789"}).Info("Created user")
+	Info("Done")
+	Flush()
+	assert.Len(t, w.Buffer(), 1)
+	assert.Contains(t, strings.Join(w.Buffer(), "\n"), "Done")
+
+	// test logs with a matching field added by a hook are suppressed
+	w.Reset()
+	AddHook(func(level Level, msg string) []Field {
+		if msg == "Created user" {
+			return []Field{{Key: "ssn", Value: "123-45-6789"}}
+		}
+		return nil
+	})
+	Info("Created user")
+	Info("Done")
+	assert.Len(t, w.Buffer(), 1)
+	assert.Contains(t, strings.Join(w.Buffer(), "\n"), "Done")
+}
+
+func TestDropField(t *testing.T) {
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{format: JSON, expected: `{"level":"info","id":42,"user":{"name":"jane"},"message":"Created user"}`},
+		{format: Logfmt, expected: `level=info msg="Created user" id=42 user.name=jane`},
+	}
+
+	defer func() { _dropFields = map[string]bool{} }()
+	DropField("ssn")
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test only the matching fields are removed, in all formats
+	for _, tc := range tests {
+		w := NewBufferedWriter(tc.format, true)
+		l := NewLogger(tc.format, true, w)
+		l.With(Field{Key: "ssn", Value: "123-45-6789"}, Field{Key: "id", Value: 42},
+			Dict("user", Field{Key: "name", Value: "jane"}, Field{Key: "ssn", Value: "123-45-6789"})).
+			Info("Created user")
+		assert.Equal(t, Buffer{tc.expected}, w.Buffer())
+	}
+}
+
+func TestDropFieldBaseFields(t *testing.T) {
+	defer func() { _dropFields = map[string]bool{} }()
+	defer func() { _dropEvents = map[string]bool{} }()
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	SetBaseFields(map[string]interface{}{"service": "api", "token": "secret"})
+	defer SetBaseFields(nil)
+	w := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(JSON, true, w)
+	defer InitLogger(Default)
+
+	// test base fields registered by DropField are removed
+	DropField("token")
+	Info("Started")
+	assert.Equal(t, Buffer{`{"level":"info","service":"api","message":"Started"}`}, w.Buffer())
+
+	// test a base field registered by DropEventsWithField suppresses all logs
+	DropEventsWithField("service")
+	Info("Suppressed")
+	assert.Len(t, w.Buffer(), 1)
+}
+
+func TestDropFieldMap(t *testing.T) {
+	defer func() { _dropFields = map[string]bool{} }()
+	defer func() { _dropEvents = map[string]bool{} }()
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	w := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, w)
+	user := map[string]interface{}{"name": "jane", "ssn": "123-45-6789",
+		"address": map[string]interface{}{"city": "Paris", "ssn": "123-45-6789"}}
+
+	// test keys of maps added by Any are removed, including nested maps, without modifying the map itself
+	DropField("ssn")
+	l.With(Any("user", user)).Info("Created user")
+	assert.Equal(t, Buffer{`{"level":"info","user":{"address":{"city":"Paris"},"name":"jane"},"message":"Created user"}`},
+		w.Buffer())
+	assert.Contains(t, user, "ssn")
+
+	// test a key of a map registered by DropEventsWithField suppresses the log
+	DropEventsWithField("city")
+	l.With(Any("user", user)).Info("Suppressed")
+	assert.Len(t, w.Buffer(), 1)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
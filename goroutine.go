@@ -0,0 +1,118 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// goroutineFieldName defines the name of the field containing the goroutine tag.
+const goroutineFieldName = "gid"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _goroutineTag indicates whether logs are tagged with a sequence number of the calling goroutine.
+var _goroutineTag bool
+
+// _goroutineTags maps the runtime ID of each goroutine that has logged a message to its sequence number.
+var _goroutineTags = make(map[uint64]uint64)
+
+// _goroutineTagLimit defines the maximum number of entries in _goroutineTags, see SetGoroutineTag.
+var _goroutineTagLimit = 10000
+
+// _goroutineSeq defines the most recently assigned goroutine sequence number.
+var _goroutineSeq uint64
+
+// _goroutineMu protects the goroutine tags and sequence number.
+var _goroutineMu sync.RWMutex
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// goroutineID returns the runtime ID of the calling goroutine, parsed from the header of its stack trace, such as
+// "goroutine 18 [running]:". It returns zero if the ID cannot be parsed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// goroutineTag returns the sequence number of the calling goroutine, assigning the next number on first use. The
+// number is reserved under an exclusive lock after confirming the goroutine has no number yet, as such no numbers are
+// skipped. The map of tags is cleared when it reaches its limit, as finished goroutines cannot be detected.
+func goroutineTag() uint64 {
+	id := goroutineID()
+	_goroutineMu.RLock()
+	tag, ok := _goroutineTags[id]
+	_goroutineMu.RUnlock()
+	if ok {
+		return tag
+	}
+
+	_goroutineMu.Lock()
+	defer _goroutineMu.Unlock()
+	if tag, ok := _goroutineTags[id]; ok {
+		return tag
+	}
+	if len(_goroutineTags) >= _goroutineTagLimit {
+		_goroutineTags = make(map[uint64]uint64)
+	}
+	_goroutineSeq++
+	_goroutineTags[id] = _goroutineSeq
+	return _goroutineSeq
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetGoroutineTag enables or disables tagging logs with a sequence number of the calling goroutine, added as "gid"
+// field. Each goroutine is assigned the next number when it logs its first message, and keeps its number afterwards.
+// This helps to attribute interleaved logs of concurrent code. Go does not expose goroutine-local storage, as such the
+// goroutine is identified by parsing the header of its stack trace. This adds roughly a microsecond to each log, and
+// retains a small map entry for every goroutine that has logged a message. As finished goroutines cannot be detected,
+// the entries are cleared once 10,000 goroutines have been tagged. A goroutine logging again after the entries have
+// been cleared is assigned a new number. Held messages are tagged when logged, not when flushed.
+func SetGoroutineTag(enabled bool) {
+	_goroutineTag = enabled
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
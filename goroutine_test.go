@@ -0,0 +1,140 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSetGoroutineTag(t *testing.T) {
+	const n = 5
+	w := &syncWriter{writer: NewBufferedWriter(JSON, true)}
+	InitLoggerWithWriter(JSON, true, w)
+	SetGlobalLevel(InfoLevel)
+	SetGoroutineTag(true)
+
+	// test each goroutine is assigned a distinct and stable tag
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Info("first")
+			Info("second")
+		}()
+	}
+	wg.Wait()
+
+	got := w.Buffer()
+	require.Len(t, got, 2*n)
+	first := make(map[float64]bool)
+	second := make(map[float64]bool)
+	for _, line := range got {
+		var evt map[string]interface{}
+		require.Nil(t, json.Unmarshal([]byte(line), &evt))
+		gid, ok := evt["gid"].(float64)
+		require.True(t, ok, line)
+		if evt["message"] == "first" {
+			first[gid] = true
+		} else {
+			second[gid] = true
+		}
+	}
+	assert.Len(t, first, n)
+	assert.Equal(t, first, second)
+
+	// test the tag is omitted when disabled
+	SetGoroutineTag(false)
+	w.writer.Reset()
+	Info("untagged")
+	require.Len(t, w.Buffer(), 1)
+	assert.NotContains(t, w.Buffer()[0], "gid")
+
+	InitLogger(Default)
+}
+
+func TestGoroutineTagLimit(t *testing.T) {
+	limit := _goroutineTagLimit
+	defer func() { _goroutineTagLimit = limit }()
+	_goroutineTagLimit = 3
+
+	// test the tags of concurrent goroutines are consecutive
+	const n = 3
+	start := _goroutineSeq
+	_goroutineTags = make(map[uint64]uint64)
+	tags := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tags <- goroutineTag()
+		}()
+	}
+	wg.Wait()
+	close(tags)
+	got := make(map[uint64]bool)
+	for tag := range tags {
+		got[tag] = true
+	}
+	assert.Equal(t, map[uint64]bool{start + 1: true, start + 2: true, start + 3: true}, got)
+
+	// test the tags are cleared once the limit is reached
+	assert.Len(t, _goroutineTags, 3)
+	tag := goroutineTag()
+	assert.Equal(t, start+4, tag)
+	assert.Len(t, _goroutineTags, 1)
+	assert.Equal(t, tag, goroutineTag())
+}
+
+func TestGoroutineTagHeld(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetGoroutineTag(true)
+	defer SetGoroutineTag(false)
+
+	// test held messages keep the tag of the goroutine that logged them, not of the flushing goroutine
+	Hold()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Info("held")
+	}()
+	<-done
+	Info("flushing")
+	Flush()
+
+	got := w.Buffer()
+	require.Len(t, got, 2)
+	tags := make([]float64, len(got))
+	for i, line := range got {
+		var evt map[string]interface{}
+		require.Nil(t, json.Unmarshal([]byte(line), &evt))
+		gid, ok := evt["gid"].(float64)
+		require.True(t, ok, line)
+		tags[i] = gid
+	}
+	assert.NotEqual(t, tags[0], tags[1])
+	assert.Equal(t, float64(goroutineTag()), tags[1])
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
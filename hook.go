@@ -0,0 +1,76 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _hooks defines the callbacks that add fields to each log, in order of registration.
+var _hooks []func(level Level, msg string) []Field
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// fieldHook implements the zerolog.Hook interface to add the fields returned by the registered hooks to each event.
+type fieldHook struct{}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// Run adds the process information, if enabled, and the fields returned by the registered hooks to the event, in order
+// of registration. The event is discarded if the fields of a hook match a filter registered by DropEventsWithField.
+func (h fieldHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	appendFields(e, _processFields)
+	for _, hook := range _hooks {
+		fields, ok := filterFields(hook(Level(level), msg))
+		if !ok {
+			e.Discard()
+			return
+		}
+		appendFields(e, fields)
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// AddHook registers a callback that returns fields to add to each log, for example to add the name and version of a
+// service. The callbacks run in order of registration, just before a log is written. As such, callbacks do not run
+// for held messages until they are flushed. The fields are added to logs of all loggers and in all formats. AddHook
+// is not safe for concurrent use with logging, register any hooks during initialization instead.
+func AddHook(hook func(level Level, msg string) []Field) {
+	_hooks = append(_hooks, hook)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
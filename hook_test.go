@@ -0,0 +1,68 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestAddHook(t *testing.T) {
+	w := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(JSON, true, w)
+	SetGlobalLevel(InfoLevel)
+	AddHook(func(level Level, msg string) []Field {
+		return []Field{{Key: "service", Value: "restic"}}
+	})
+	AddHook(func(level Level, msg string) []Field {
+		return []Field{{Key: "version", Value: "1.0.0"}, {Key: "severity", Value: level.String()}}
+	})
+
+	// test the fields are added in order of registration
+	Warn("Listing snapshots")
+	require.Len(t, w.Buffer(), 1)
+	assert.Regexp(t, `^{"level":"warn","time":"\S+","service":"restic","version":"1.0.0","severity":"warn",`+
+		`"message":"Listing snapshots"}$`, w.Buffer()[0])
+
+	// test the hooks do not run for held messages until flushed
+	calls := 0
+	AddHook(func(level Level, msg string) []Field {
+		calls++
+		return nil
+	})
+	Hold()
+	Info("Held message")
+	assert.Equal(t, 0, calls)
+	Flush()
+	assert.Equal(t, 1, calls)
+	require.Len(t, w.Buffer(), 2)
+	assert.Contains(t, w.Buffer()[1], `"service":"restic"`)
+
+	// test the fields are rendered in the other formats too
+	SetFormatting(Logfmt, true)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 3)
+	assert.Contains(t, w.Buffer()[2], "service=restic")
+
+	_hooks = nil
+	InitLogger(Default)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,321 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// Defines the default options of an HTTPWriter.
+const (
+	defaultHTTPBatchSize      = 100
+	defaultHTTPFlushInterval  = time.Second
+	defaultHTTPMaxRetries     = 3
+	defaultHTTPRetryDelay     = 100 * time.Millisecond
+	defaultHTTPPendingBatches = 10
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// HTTPWriterOptions defines the options of an HTTPWriter. Zero values are replaced by their defaults.
+type HTTPWriterOptions struct {
+	// BatchSize defines the maximum number of logs per request, defaults to 100. A batch is sent as soon as it is full.
+	BatchSize int
+
+	// FlushInterval defines the maximum time logs are buffered before they are sent, defaults to one second.
+	FlushInterval time.Duration
+
+	// MaxPending defines the maximum number of logs queued while the endpoint is slow or unavailable, defaults to 10
+	// times BatchSize. When exceeded, the oldest batch of logs is dropped and counted, see DroppedBatches.
+	MaxPending int
+
+	// Headers defines additional request headers, for example to authenticate with the collector.
+	Headers map[string]string
+
+	// NDJSON sends the logs as newline-delimited JSON instead of a JSON array.
+	NDJSON bool
+
+	// MaxRetries defines the number of retries of a batch after a transient error, defaults to 3. Use a negative value
+	// to disable retries.
+	MaxRetries int
+
+	// RetryDelay defines the delay before the first retry, defaults to 100ms. The delay increases linearly with each
+	// retry.
+	RetryDelay time.Duration
+
+	// Client defines the HTTP client to send the requests, defaults to a client with a timeout of 10 seconds.
+	Client *http.Client
+}
+
+// HTTPWriter implements a log writer that sends logs in batches to an HTTP endpoint, such as a log collector. Logs
+// are always sent as JSON, regardless of the active format. Batches are sent by a background goroutine when full or
+// when the flush interval has elapsed. Batches that cannot be delivered after the configured retries are dropped and
+// counted, see DroppedBatches. Batches dropped as the queue is full are counted too.
+type HTTPWriter struct {
+	dropped uint64 // accessed atomically, keep first for alignment
+	url     string
+	opts    HTTPWriterOptions
+	pending [][]byte
+	closed  bool
+	mu      sync.Mutex // protects pending and closed
+	sendMu  sync.Mutex // ensures batches are sent in order
+	full    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// post sends a single batch to the endpoint, retrying after transient errors. Network errors, server errors, and
+// responses with status 429 (Too Many Requests) are considered transient. It returns an error if the batch cannot be
+// delivered, or if the context is done.
+func (w *HTTPWriter) post(ctx context.Context, batch [][]byte) error {
+	var body []byte
+	contentType := "application/json"
+	if w.opts.NDJSON {
+		contentType = "application/x-ndjson"
+		body = append(bytes.Join(batch, []byte("\n")), '\n')
+	} else {
+		body = append(append([]byte("["), bytes.Join(batch, []byte(","))...), ']')
+	}
+
+	var err error
+	for attempt := 0; attempt <= w.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * w.opts.RetryDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		var retry bool
+		if retry, err = w.request(ctx, body, contentType); err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// request sends a single request to the endpoint. It returns an error if the request fails, and whether the request
+// can be retried.
+func (w *HTTPWriter) request(ctx context.Context, body []byte, contentType string) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("Cannot create request: %s", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range w.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("Cannot send logs: %s", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck // drain the body to reuse the connection
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("Cannot send logs, got status %d", resp.StatusCode)
+}
+
+// run sends the pending logs whenever a batch is full or the flush interval has elapsed, until the writer is closed.
+func (w *HTTPWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.send(context.Background()) //nolint:errcheck // failed batches are counted as dropped
+		case <-w.full:
+			w.send(context.Background()) //nolint:errcheck // failed batches are counted as dropped
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// send sends all pending logs in batches of the configured size. Batches that cannot be delivered, including batches
+// remaining once the context is done, are dropped. It returns the error of the last failed batch, if any.
+func (w *HTTPWriter) send(ctx context.Context) error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	var err error
+	for len(pending) > 0 {
+		n := w.opts.BatchSize
+		if n > len(pending) {
+			n = len(pending)
+		}
+		if e := w.post(ctx, pending[:n]); e != nil {
+			atomic.AddUint64(&w.dropped, 1)
+			err = e
+		}
+		pending = pending[n:]
+	}
+	return err
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewHTTPWriter creates a log writer that sends logs in batches to the endpoint at url using POST requests. The logs
+// are sent as JSON array, or as newline-delimited JSON if configured. Call Close to send the pending logs and to stop
+// the background goroutine.
+func NewHTTPWriter(url string, opts HTTPWriterOptions) Writer {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultHTTPBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultHTTPFlushInterval
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = defaultHTTPPendingBatches * opts.BatchSize
+	} else if opts.MaxPending < opts.BatchSize {
+		opts.MaxPending = opts.BatchSize
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultHTTPMaxRetries
+	} else if opts.MaxRetries < 0 {
+		// a negative value disables retries, while still sending the first attempt
+		opts.MaxRetries = 0
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultHTTPRetryDelay
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	w := &HTTPWriter{
+		url:  url,
+		opts: opts,
+		full: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+// Close sends the pending logs and stops the background goroutine. Subsequent writes return an error. Calling Close
+// more than once has no effect. It returns an error if the pending logs cannot be delivered.
+func (w *HTTPWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.done
+	return w.send(context.Background())
+}
+
+// DroppedBatches returns the number of batches that could not be delivered, including batches dropped as the queue
+// exceeded MaxPending.
+func (w *HTTPWriter) DroppedBatches() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Flush sends the pending logs immediately. It returns an error if the pending logs cannot be delivered.
+func (w *HTTPWriter) Flush() error {
+	return w.send(context.Background())
+}
+
+// FlushContext sends all pending logs similar to Flush, but abandons sending once the context is done. Any batches
+// that have not been sent by then are dropped and counted by DroppedBatches.
+func (w *HTTPWriter) FlushContext(ctx context.Context) error {
+	return w.send(ctx)
+}
+
+// SetFormatting is a no-op for HTTPWriter, as logs are always sent as JSON.
+func (w *HTTPWriter) SetFormatting(format Format, noColor bool) {}
+
+// Write implements the io.Writer interface for HTTPWriter. It queues a copy of the JSON-formatted log p and returns
+// immediately. Write returns an error if the writer has been closed.
+func (w *HTTPWriter) Write(p []byte) (n int, err error) {
+	line := bytes.TrimRight(stripByteSizes(p), "\n")
+	if len(line) == 0 {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errors.New("Cannot write to closed HTTP writer")
+	}
+
+	// drop the oldest batch if the queue is full, as the endpoint cannot keep up
+	if len(w.pending) >= w.opts.MaxPending {
+		n := copy(w.pending, w.pending[w.opts.BatchSize:])
+		w.pending = w.pending[:n]
+		atomic.AddUint64(&w.dropped, 1)
+	}
+
+	// copy the input, as the caller may reuse the underlying buffer
+	w.pending = append(w.pending, append([]byte(nil), line...))
+	if len(w.pending) >= w.opts.BatchSize {
+		select {
+		case w.full <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
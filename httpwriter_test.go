@@ -0,0 +1,204 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+// collector defines a test server that records the received batches, failing the first requests if needed.
+type collector struct {
+	mu      sync.Mutex
+	batches [][]map[string]interface{}
+	headers []http.Header
+	fail    int
+	status  int
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.headers = append(c.headers, r.Header.Clone())
+	if c.fail != 0 {
+		if c.fail > 0 {
+			c.fail--
+		}
+		w.WriteHeader(c.status)
+		return
+	}
+
+	var batch []map[string]interface{}
+	body, _ := io.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.batches = append(c.batches, batch)
+}
+
+func (c *collector) received() [][]map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]map[string]interface{}{}, c.batches...)
+}
+
+func TestHTTPWriter(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	w := NewHTTPWriter(server.URL, HTTPWriterOptions{
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		Headers:       map[string]string{"Authorization": "Bearer secret"},
+	})
+	InitLoggerWithWriter(Pretty, true, w)
+	SetGlobalLevel(InfoLevel)
+
+	// test full batches are sent, forcing JSON
+	Info("first")
+	Info("second")
+	require.Eventually(t, func() bool { return len(c.received()) == 1 }, time.Second, 5*time.Millisecond)
+	batch := c.received()[0]
+	require.Len(t, batch, 2)
+	assert.Equal(t, "first", batch[0]["message"])
+	assert.Equal(t, "second", batch[1]["message"])
+	assert.Equal(t, "Bearer secret", c.headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", c.headers[0].Get("Content-Type"))
+
+	// test the pending logs are sent on close
+	Info("third")
+	require.Nil(t, w.(io.Closer).Close())
+	got := c.received()
+	require.Len(t, got, 2)
+	require.Len(t, got[1], 1)
+	assert.Equal(t, "third", got[1][0]["message"])
+	_, err := w.Write([]byte(`{"message":"closed"}`))
+	assert.NotNil(t, err)
+
+	InitLogger(Default)
+}
+
+func TestHTTPWriterInterval(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	// test the logs are sent when the flush interval elapses
+	w := NewHTTPWriter(server.URL, HTTPWriterOptions{FlushInterval: 10 * time.Millisecond})
+	defer w.(io.Closer).Close()
+	_, err := w.Write([]byte(`{"level":"info","message":"first"}` + "\n"))
+	require.Nil(t, err)
+	assert.Eventually(t, func() bool { return len(c.received()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "first", c.received()[0][0]["message"])
+}
+
+func TestHTTPWriterRetries(t *testing.T) {
+	c := &collector{fail: 2, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	// test transient errors are retried
+	w := NewHTTPWriter(server.URL, HTTPWriterOptions{FlushInterval: time.Hour, RetryDelay: time.Millisecond})
+	_, err := w.Write([]byte(`{"message":"first"}`))
+	require.Nil(t, err)
+	require.Nil(t, w.(*HTTPWriter).Flush())
+	assert.Len(t, c.received(), 1)
+	assert.Equal(t, uint64(0), w.(*HTTPWriter).DroppedBatches())
+
+	// test batches are dropped after the retries are exhausted
+	c.mu.Lock()
+	c.fail = -1
+	c.mu.Unlock()
+	_, err = w.Write([]byte(`{"message":"second"}`))
+	require.Nil(t, err)
+	assert.NotNil(t, w.(*HTTPWriter).Flush())
+	assert.Equal(t, uint64(1), w.(*HTTPWriter).DroppedBatches())
+	c.mu.Lock()
+	assert.Len(t, c.headers, 7)
+	c.mu.Unlock()
+
+	// test client errors are not retried
+	c.mu.Lock()
+	c.status = http.StatusUnauthorized
+	c.mu.Unlock()
+	_, err = w.Write([]byte(`{"message":"third"}`))
+	require.Nil(t, err)
+	assert.EqualError(t, w.(io.Closer).Close(), "Cannot send logs, got status 401")
+	assert.Equal(t, uint64(2), w.(*HTTPWriter).DroppedBatches())
+	c.mu.Lock()
+	assert.Len(t, c.headers, 8)
+	c.mu.Unlock()
+}
+
+func TestHTTPWriterNoRetries(t *testing.T) {
+	c := &collector{fail: -1, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	// test a negative number of retries sends a single attempt and drops the batch on failure
+	w := NewHTTPWriter(server.URL, HTTPWriterOptions{FlushInterval: time.Hour, MaxRetries: -1})
+	_, err := w.Write([]byte(`{"message":"first"}`))
+	require.Nil(t, err)
+	assert.NotNil(t, w.(*HTTPWriter).Flush())
+	assert.Equal(t, uint64(1), w.(*HTTPWriter).DroppedBatches())
+	c.mu.Lock()
+	assert.Len(t, c.headers, 1)
+	c.mu.Unlock()
+
+	// test the batch is delivered when the endpoint succeeds
+	c.mu.Lock()
+	c.fail = 0
+	c.mu.Unlock()
+	_, err = w.Write([]byte(`{"message":"second"}`))
+	require.Nil(t, err)
+	require.Nil(t, w.(io.Closer).Close())
+	assert.Len(t, c.received(), 1)
+}
+
+func TestHTTPWriterMaxPending(t *testing.T) {
+	// test the queue defaults to ten batches and holds at least a single batch
+	w := NewHTTPWriter("http://localhost", HTTPWriterOptions{BatchSize: 5}).(*HTTPWriter)
+	assert.Equal(t, 50, w.opts.MaxPending)
+	require.Nil(t, w.Close())
+	w = NewHTTPWriter("http://localhost", HTTPWriterOptions{BatchSize: 5, MaxPending: 2}).(*HTTPWriter)
+	assert.Equal(t, 5, w.opts.MaxPending)
+	require.Nil(t, w.Close())
+
+	// test the oldest batch is dropped and counted when the queue is full, without a background goroutine sending it
+	w = &HTTPWriter{opts: HTTPWriterOptions{BatchSize: 2, MaxPending: 4}}
+	for i := 0; i < 5; i++ {
+		_, err := w.Write([]byte(fmt.Sprintf(`{"message":"%d"}`, i)))
+		require.Nil(t, err)
+	}
+	assert.Equal(t, [][]byte{[]byte(`{"message":"2"}`), []byte(`{"message":"3"}`), []byte(`{"message":"4"}`)},
+		w.pending)
+	assert.Equal(t, uint64(1), w.DroppedBatches())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,114 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _levelPrefix matches a level prefix of a log line, such as "ERROR:" or "[warn]", including any trailing whitespace.
+var _levelPrefix = regexp.MustCompile(`^\s*(?:\[([A-Za-z]+)\]|([A-Za-z]+):)\s*`)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// LevelInferringWriter implements an io.Writer that logs each written line to the global logger, inferring the level
+// from the prefix of the line. It is typically used to capture the output of tools or libraries that prefix their
+// logs with a level, such as "ERROR: cannot connect".
+type LevelInferringWriter struct {
+	fallback Level
+	mu       sync.Mutex
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// inferLevel returns the level indicated by the prefix of the line and the line without the prefix. Prefixes are
+// recognized when followed by a colon or enclosed in square brackets, using the names and abbreviations of at least
+// three characters supported by ParseLevelLenient. It returns false if the line has no recognized prefix.
+func inferLevel(line string) (Level, string, bool) {
+	m := _levelPrefix.FindStringSubmatch(line)
+	if m == nil {
+		return 0, line, false
+	}
+
+	name := m[1] + m[2]
+	l, err := ParseLevelLenient(name)
+	if err != nil || len(name) < 3 || l == Disabled {
+		return 0, line, false
+	}
+	return l, line[len(m[0]):], true
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewLevelInferringWriter creates a writer that logs each written line to the global logger, using the level indicated
+// by the prefix of the line. Recognized prefixes are case-insensitive level names followed by a colon or enclosed in
+// square brackets, such as "ERROR:", "Warning:", "[debug]", or "[ERR]". The prefix is stripped from the message. Lines
+// without a recognized prefix are logged at InfoLevel, use SetDefaultLevel to change the fallback level. Fatal lines
+// are logged without exiting the program. Empty lines are skipped.
+func NewLevelInferringWriter() *LevelInferringWriter {
+	return &LevelInferringWriter{fallback: InfoLevel}
+}
+
+// SetDefaultLevel sets the level of lines without a recognized prefix.
+func (w *LevelInferringWriter) SetDefaultLevel(level Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.fallback = level
+}
+
+// Write implements the io.Writer interface for LevelInferringWriter.
+func (w *LevelInferringWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	fallback := w.fallback
+	w.mu.Unlock()
+
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		level, msg, ok := inferLevel(line)
+		if !ok {
+			level = fallback
+		}
+		_logger.log(level, nil, msg, nil)
+	}
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,74 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestLevelInferringWriter(t *testing.T) {
+	type test struct {
+		input    string
+		level    Level
+		expected string
+	}
+	var tests = []test{
+		{input: "ERROR: Cannot connect", level: ErrorLevel, expected: "Cannot connect"},
+		{input: "WARN: Connection slow", level: WarnLevel, expected: "Connection slow"},
+		{input: "Warning:Connection slow", level: WarnLevel, expected: "Connection slow"},
+		{input: "[debug] Listing snapshots", level: DebugLevel, expected: "Listing snapshots"},
+		{input: "  [ERR]  Cannot connect", level: ErrorLevel, expected: "Cannot connect"},
+		{input: "trace: Reading file", level: TraceLevel, expected: "Reading file"},
+		{input: "FATAL: Cannot recover", level: FatalLevel, expected: "Cannot recover"},
+		{input: "Error occurred while listing", level: InfoLevel, expected: "Error occurred while listing"},
+		{input: "E: Cannot connect", level: InfoLevel, expected: "E: Cannot connect"},
+		{input: "Note: Listing snapshots", level: InfoLevel, expected: "Note: Listing snapshots"},
+	}
+
+	w := newTestLogger(t, JSON)
+	infer := NewLevelInferringWriter()
+	for _, test := range tests {
+		w.Reset()
+		n, err := infer.Write([]byte(test.input + "\n"))
+		require.Nil(t, err, test.input)
+		assert.Equal(t, len(test.input)+1, n, test.input)
+		require.Len(t, w.Buffer(), 1, test.input)
+		m, err := UnmarshalLog([]byte(w.Buffer()[0]))
+		require.Nil(t, err, test.input)
+		assert.Equal(t, test.level, m.Level, test.input)
+		assert.Equal(t, test.expected, m.Message, test.input)
+	}
+
+	// test the fallback level and multi-line input
+	infer.SetDefaultLevel(DebugLevel)
+	w.Reset()
+	_, err := infer.Write([]byte("Listing snapshots\n\nERROR: Cannot connect\n"))
+	require.Nil(t, err)
+	require.Len(t, w.Buffer(), 2)
+	if got, ok := w.LevelAt(0); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, DebugLevel, got, w.Buffer())
+	}
+	if got, ok := w.LevelAt(1); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, ErrorLevel, got, w.Buffer())
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
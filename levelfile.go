@@ -0,0 +1,101 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _levelFileInterval defines the interval at which WatchLevelFile polls the level file for changes.
+var _levelFileInterval = time.Second
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// applyLevelFile sets the global level to the level defined by the content of a level file. Invalid content is ignored
+// with a warning, keeping the current level.
+func applyLevelFile(path string, content []byte) {
+	level, err := ParseLevelLenient(strings.TrimSpace(string(content)))
+	if err != nil {
+		Warnf("Cannot parse level file '%s', keeping level '%s'", path, GlobalLevel())
+		return
+	}
+	SetGlobalLevel(level)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// WatchLevelFile sets the global level to the level defined in a file, and polls the file every second to apply any
+// changes, for example to increase the verbosity of a running process without a restart. The file contains a single
+// level, such as "debug", as accepted by ParseLevelLenient. Invalid content is ignored with a warning, keeping the
+// current level. The file may be removed temporarily, for example while being replaced by an editor. WatchLevelFile
+// returns an error if the file cannot be read initially. The returned function stops watching the file.
+func WatchLevelFile(path string) (stop func(), err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read level file: %s", err.Error())
+	}
+	applyLevelFile(path, content)
+
+	// poll the file until stopped
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(_levelFileInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c, err := os.ReadFile(path)
+				if err != nil || bytes.Equal(c, content) {
+					continue
+				}
+				content = c
+				applyLevelFile(path, content)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
+	return stop, nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
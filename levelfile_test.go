@@ -0,0 +1,65 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestWatchLevelFile(t *testing.T) {
+	w := newTestLogger(t, Default)
+	defer func(d time.Duration) { _levelFileInterval = d }(_levelFileInterval)
+	_levelFileInterval = 10 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "level")
+
+	// test a missing file returns an error
+	_, err := WatchLevelFile(path)
+	assert.EqualError(t, err, "Cannot read level file: open "+path+": no such file or directory")
+
+	// test the initial level is applied
+	require.NoError(t, os.WriteFile(path, []byte("debug\n"), 0600))
+	stop, err := WatchLevelFile(path)
+	require.NoError(t, err)
+	defer stop()
+	assert.Equal(t, DebugLevel, GlobalLevel())
+
+	// test invalid content is ignored with a warning
+	require.NoError(t, os.WriteFile(path, []byte("loud"), 0600))
+	assert.Eventually(t, func() bool { return len(w.Buffer()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Contains(t, strings.Join(w.Buffer(), "\n"), "Cannot parse level file")
+	assert.Equal(t, DebugLevel, GlobalLevel())
+
+	// test the global level follows changes
+	require.NoError(t, os.WriteFile(path, []byte("error"), 0600))
+	assert.Eventually(t, func() bool { return GlobalLevel() == ErrorLevel }, time.Second, 5*time.Millisecond)
+
+	// test changes are ignored after stopping
+	stop()
+	require.NoError(t, os.WriteFile(path, []byte("warn"), 0600))
+	time.Sleep(5 * _levelFileInterval)
+	assert.Equal(t, ErrorLevel, GlobalLevel())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
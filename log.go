@@ -2,9 +2,9 @@
 // Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
 
 // Package log is a simplified logger package for Go applications. Using the Zero Allocation JSON Logger
-// (zerolog) under the hood, it simplifies the logging of application-wide messages. It supports three logging modes:
-// Default, Pretty, and JSON. Logs are directed to the console by default, but can be buffered or redirected to a log
-// file instead.
+// (zerolog) under the hood, it simplifies the logging of application-wide messages. It supports five logging modes:
+// Default, Pretty, JSON (compact or indented), Logfmt, and CSV. Logs are directed to the console by default, but can be
+// buffered or redirected to a log file instead.
 package log
 
 //======================================================================================================================
@@ -12,12 +12,17 @@ package log
 //======================================================================================================================
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -44,6 +49,23 @@ const (
 	// JSON prints logs as JSON strings, for example:
 	// 		// {"level":"info","time":"2020-12-17T07:12:57+01:00","message":"Listing snapshots"}
 	JSON
+
+	// Logfmt prints logs as key/value pairs using the logfmt convention, for example:
+	// 		// level=info msg="Listing snapshots" time=2020-12-17T07:12:57+01:00
+	Logfmt
+
+	// JSONIndent prints logs as indented JSON strings across multiple lines, for example:
+	// 		// {
+	// 		//   "level": "info",
+	// 		//   "time": "2020-12-17T07:12:57+01:00",
+	// 		//   "message": "Listing snapshots"
+	// 		// }
+	JSONIndent
+
+	// CSV prints logs as comma-separated values with the columns time, level, message, and error, for example:
+	// 		// time,level,message,error
+	// 		// 2020-12-17T07:12:57+01:00,info,Listing snapshots,
+	CSV
 )
 
 // Defines a pseudo enumeration of possible logging levels, copied from zerolog to hide implementation details.
@@ -85,11 +107,38 @@ const (
 //======================================================================================================================
 
 // _logger is used as internal handler for any logs to be created by the functions Info(), Debug(), et al.
-var _logger = NewLogger(Default, false)
+var _logger = newDefaultLogger()
+
+// _initPending indicates the global logger holds all logs until it is initialized, see newDefaultLogger.
+var _initPending bool
+
+// _timeFormat defines the layout of timestamps, used by all formats and by UnmarshalLog.
+var _timeFormat = time.RFC3339
+
+// _sampling defines the sample rate of logs per level, a value of 1 or less disables sampling.
+var _sampling int
+
+// _timestamp indicates whether logs include a timestamp.
+var _timestamp = true
+
+// _baseFields defines the fields added to each log by the handler, see SetBaseFields.
+var _baseFields map[string]interface{}
+
+// _enabledLevel defines the global level to restore when logging is enabled again, see Disable and Enable.
+var _enabledLevel = GlobalLevel()
+
+// _fatalHooks defines the callbacks to run before a Fatal log exits the program.
+var _fatalHooks []func()
+
+// _fatalExitCode defines the exit code of Fatal logs, see SetFatalExitCode.
+var _fatalExitCode = 1
 
 // _suppressExit suppresses Fatal logs from exiting the program. Used for testing.
 var _suppressExit bool
 
+// _exitCode records the exit code of the last Fatal log when the exit is suppressed. Used for testing.
+var _exitCode int
+
 //======================================================================================================================
 // endregion
 //======================================================================================================================
@@ -105,11 +154,20 @@ type Writer interface {
 	SetFormatting(format Format, noColor bool)
 }
 
-// Logger is a simplified logger that uses zerolog under the hood. It supports three logging modes, being Default,
-// Pretty, and JSON. In default mode, all logs are printed using simplified formatting. This format omits timestamps and
-// puts a simple keyword in front of the message to indicate the level. For Info logs, the level is omitted. Pretty mode
-// structures the logs using a timestamp (RFC 3339) and level indicator, separated by the symbol '|'. Finally, JSON mode
-// formats the log as a JSON message, consisting of the attributes timestamp (RFC 3339), level, and message.
+// Flusher defines the interface for writers that buffer logs internally, such as AsyncWriter and HTTPWriter. Flush
+// writes the buffered logs to their destination. See Sync.
+type Flusher interface {
+	Flush() error
+}
+
+// Logger is a simplified logger that uses zerolog under the hood. It supports five logging modes, being Default,
+// Pretty, JSON, Logfmt, and CSV. In default mode, all logs are printed using simplified formatting. This format omits
+// timestamps and puts a simple keyword in front of the message to indicate the level. For Info logs, the level is
+// omitted. Pretty mode structures the logs using a timestamp (RFC 3339) and level indicator, separated by the symbol
+// '|'. JSON mode formats the log as a JSON message, consisting of the attributes timestamp (RFC 3339), level, and
+// message, while JSONIndent mode renders the same message indented across multiple lines. Finally, Logfmt mode renders
+// the same attributes as space-separated key/value pairs, quoting values that contain spaces. CSV mode renders the
+// timestamp, level, message, and error as comma-separated values, preceded by a header row.
 //
 // A default logger is instantiated by default. The following examples illustrate how to use the package.
 //
@@ -152,10 +210,16 @@ type Logger struct {
 	writers []Writer
 	noColor bool
 	buffer  []Message
+	holdMax int
 	hold    bool
+
+	untilError   bool // flush the buffer on the first error, see HoldUntilError
+	flushOnClose bool // flush the buffer on Close, instead of discarding it
+
+	mu sync.RWMutex // protects the buffer and hold state
 }
 
-// Format defines the type of logging format to use, either Default, Pretty, or JSON.
+// Format defines the type of logging format to use, either Default, Pretty, JSON, Logfmt, JSONIndent, or CSV.
 type Format int
 
 // Level defines the minimum level of logs to display. Supported levels are DebugLevel, InfoLevel, WarnLevel,
@@ -163,15 +227,49 @@ type Format int
 // underlying zerolog package.
 type Level int8
 
+// FieldNames defines the names of the standard fields of JSON-formatted logs. Empty names are replaced by their
+// defaults, being "time", "level", and "message".
+type FieldNames struct {
+	Timestamp string
+	Level     string
+	Message   string
+}
+
 // Message defines the structure of JSON-formatted log messages produced by zerolog.
 type Message struct {
 	Level   Level
 	Time    time.Time
 	Message string
 	Error   string
+	fields  []Field
 	err     error
+	tags    logTags
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// bypassWriter defines the interface for writers that can render logs in Default format, regardless of their current
+// format. It is used by Bypass to write logs without changing the format of the writer.
+type bypassWriter interface {
+	writeBypass(p []byte) (n int, err error)
+}
+
+// fixedFormatWriter wraps a Writer to keep its log format fixed, regardless of the format of the Logger. The color
+// coding still follows the Logger.
+type fixedFormatWriter struct {
+	Writer
+	format Format
 }
 
+// multiError combines multiple errors into a single error, separating the messages by a semicolon.
+type multiError []error
+
 //======================================================================================================================
 // endregion
 //======================================================================================================================
@@ -180,10 +278,110 @@ type Message struct {
 // region Private Functions
 //======================================================================================================================
 
-// getWriterIndex returns the index of the Writer within the list of writers known by Logger. It returns -1 if the
-// writer cannot be found.
+// Error implements the error interface for multiError.
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Close closes the wrapped writer if it implements io.Closer.
+func (w *fixedFormatWriter) Close() error {
+	if c, ok := w.Writer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Flush flushes the wrapped writer, if it implements Flusher.
+func (w *fixedFormatWriter) Flush() error {
+	return flush(w.Writer)
+}
+
+// FlushContext flushes the wrapped writer until the context is done, see FlushWithTimeout.
+func (w *fixedFormatWriter) FlushContext(ctx context.Context) error {
+	return flushWriter(ctx, w.Writer)
+}
+
+// SetFormatting updates the color coding of the wrapped writer, using the fixed log format.
+func (w *fixedFormatWriter) SetFormatting(format Format, noColor bool) {
+	w.Writer.SetFormatting(w.format, noColor)
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for fixedFormatWriter. It passes the level to the wrapped
+// writer if supported, so writers that route logs by level, such as a split file writer, keep working.
+func (w *fixedFormatWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	if lw, ok := w.Writer.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Writer.Write(p)
+}
+
+// writeBypass writes the log to the wrapped writer in Default format, if supported.
+func (w *fixedFormatWriter) writeBypass(p []byte) (n int, err error) {
+	return bypass(w.Writer, p)
+}
+
+// flush flushes w if it implements Flusher, for use by writers wrapping another writer.
+func flush(w io.Writer) error {
+	if f, ok := w.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// writeLevel writes the log p to w, passing the level if w implements zerolog.LevelWriter. Logs without a level
+// (NoLevel) are written using Write.
+func writeLevel(w io.Writer, level zerolog.Level, p []byte) (n int, err error) {
+	if lw, ok := w.(zerolog.LevelWriter); ok && level != zerolog.NoLevel {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Write(p)
+}
+
+// bypass writes the JSON-formatted log p to the writer in Default format, if supported. Other writers receive the log
+// as-is.
+func bypass(w Writer, p []byte) (n int, err error) {
+	if b, ok := w.(bypassWriter); ok {
+		return b.writeBypass(p)
+	}
+	return w.Write(p)
+}
+
+// exit flushes all writers using Sync, runs the callbacks registered by OnFatal in reverse order of registration, and
+// exits the program with the given exit code. The writers are flushed and the callbacks run even when the exit is
+// suppressed for testing, in which case the exit code is recorded.
+func exit(code int) {
+	Sync() //nolint:errcheck // errors cannot be reported back to the caller
+	for i := len(_fatalHooks) - 1; i >= 0; i-- {
+		_fatalHooks[i]()
+	}
+
+	if _suppressExit {
+		_exitCode = code
+		return
+	}
+	os.Exit(code)
+}
+
+// formatMessage formats the message using the arguments. The message is returned verbatim if there are no arguments,
+// so a literal '%' is not mistaken for a formatting verb.
+func formatMessage(msg string, v []interface{}) string {
+	if len(v) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, v...)
+}
+
+// getWriterIndex returns the index of the Writer within the list of writers known by Logger. Writers added by
+// AppendWriterWithFormat are matched by the writer they wrap. It returns -1 if the writer cannot be found.
 func getWriterIndex(w Writer) int {
 	for index, curr := range _logger.writers {
+		if f, ok := curr.(*fixedFormatWriter); ok && w == f.Writer {
+			return index
+		}
 		if w == curr {
 			return index
 		}
@@ -192,32 +390,253 @@ func getWriterIndex(w Writer) int {
 	return -1
 }
 
-// log is an internal function to redirect logging requests to either the handler or local buffer.
-func log(level Level, msg string, err error, v ...interface{}) {
-	var m string
-	if v != nil {
-		m = fmt.Sprintf(msg, v...)
-	} else {
-		m = msg
+// parseTime converts a raw JSON timestamp into a typed time. It tries the configured time format first, followed by the
+// RFC 3339 and RFC 3339 (nano) layouts for string values. It interprets integer values as Unix timestamps. Unless the
+// configured time format specifies a Unix precision, integers with an absolute value of 1e12 or more are considered to
+// be milliseconds, and seconds otherwise.
+func parseTime(raw json.RawMessage) (time.Time, error) {
+	const millisThreshold = 1e12
+	layouts := []string{_timeFormat, time.RFC3339, time.RFC3339Nano}
+
+	// try the string layouts first, accept quoted integers too
+	value := string(raw)
+	if s, err := strconv.Unquote(value); err == nil {
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		value = s
 	}
 
-	if _logger.hold {
-		var log Message
-		log.Level = level
-		log.Time = time.Now()
-		log.Message = m
-		log.err = err
-		if err != nil {
-			log.Error = err.Error()
+	// interpret integers as Unix timestamps
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch _timeFormat {
+		case zerolog.TimeFormatUnix:
+			return time.Unix(n, 0), nil
+		case zerolog.TimeFormatUnixMs:
+			return time.Unix(0, n*int64(time.Millisecond)), nil
+		case zerolog.TimeFormatUnixMicro:
+			return time.Unix(0, n*int64(time.Microsecond)), nil
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, n), nil
+		}
+		if n >= millisThreshold || n <= -millisThreshold {
+			return time.Unix(0, n*int64(time.Millisecond)), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("Cannot parse datetime format, got %s, want one of %s, %s, Unix seconds, "+
+		"Unix milliseconds", raw, time.RFC3339, time.RFC3339Nano)
+}
+
+// newDefaultLogger creates the global logger using Default format. If the environment variable LOG_DEFER_INIT is set to
+// a true value, the logger holds all logs until it is initialized by InitLogger, InitLoggerWithWriter, or InitFromEnv.
+// This ensures logs written during package initialization, for example by a library, are written to the destination
+// chosen by the application.
+func newDefaultLogger() *Logger {
+	l := NewLogger(Default, false)
+	if b, err := strconv.ParseBool(os.Getenv(envDeferInit)); err == nil && b {
+		l.hold = true
+		_initPending = true
+	}
+	return l
+}
+
+// releaseInit writes the logs held by the global logger since the start of the program, if LOG_DEFER_INIT is set.
+func releaseInit() {
+	if _initPending {
+		_initPending = false
+		_logger.Flush()
+	}
+}
+
+// writeLine writes a single line received by Write at the given level. Empty lines are skipped, unless the Logger uses
+// Default format.
+func (l *Logger) writeLine(level Level, line string) {
+	if line != "" || l.format == Default {
+		writeEvent(l.handler, level, nil, line, nil, nil)
+	}
+}
+
+// writeEvent writes a log message with the fields and error to the zerolog handler, adding the stack trace and error
+// chain and type of the error if enabled. The tags recorded when the log was held are added if given, otherwise the
+// tags are assigned now, unless the log is dropped by sampling. Logs with a field registered by DropEventsWithField
+// are suppressed.
+func writeEvent(handler *zerolog.Logger, level Level, fields []Field, msg string, err error, tags *logTags) {
+	fields, ok := filterFields(fields)
+	if !ok {
+		return
+	}
+
+	e := handler.WithLevel(zerolog.Level(level))
+	if e == nil {
+		return
+	}
+	e = appendFields(e, fields)
+	if paths := byteSizePaths(fields, ""); paths != nil {
+		e = e.Strs(byteSizesFieldName, paths)
+	}
+	if err != nil {
+		if _stackTrace {
+			e = e.Stack()
+		}
+		if chain := errorChain(err); _errorChain && chain != nil {
+			e = e.Strs(errorChainFieldName, chain)
+		}
+		if _errorType {
+			e = e.Str(errorTypeFieldName, fmt.Sprintf("%T", err))
 		}
-		_logger.buffer = append(_logger.buffer, log)
+		e = e.Err(err)
+	}
+	if tags == nil {
+		t := newLogTags()
+		tags = &t
+	}
+	tags.appendTo(e).Msg(msg)
+}
+
+// initLogger replaces the global logger with a new logger using the desired format, writer(s), and color coding. The
+// held messages of the current logger are preserved. The new logger keeps holding logs if the global logger has not
+// been initialized yet, see newDefaultLogger, or if the current logger holds logs until an error, see HoldUntilError.
+func initLogger(format Format, noColor bool, writer ...Writer) {
+	b := _logger.HeldMessages()
+	_logger.mu.RLock()
+	max := _logger.holdMax
+	untilError := _logger.hold && _logger.untilError
+	flushOnClose := _logger.flushOnClose
+	_logger.mu.RUnlock()
+
+	_logger = NewLogger(format, noColor, writer...)
+	_logger.buffer = b
+	_logger.holdMax = max
+	_logger.hold = _initPending || untilError
+	_logger.untilError = untilError
+	_logger.flushOnClose = flushOnClose
+}
+
+// withBaseFields adds the base fields to the handler, omitting the fields registered by DropField. The handler is
+// disabled if a base field is registered by DropEventsWithField, as each log would be suppressed.
+func withBaseFields(handler zerolog.Logger) zerolog.Logger {
+	fields, ok := filterMap(_baseFields)
+	if !ok {
+		return handler.Level(zerolog.Disabled)
+	}
+	if len(fields) > 0 {
+		handler = handler.With().Fields(fields).Logger()
+	}
+	return handler
+}
+
+// initHandler initializes the zerolog handler of the Logger using either a single writer or a multi-level writer. It
+// applies the package-wide settings, such as sampling, and reports write errors to the write error handler.
+func (l *Logger) initHandler() {
+	var handler zerolog.Logger
+	if len(l.writers) == 1 {
+		handler = zerolog.New(&errorWriter{writer: l.writers[0]})
 	} else {
-		if err != nil {
-			_logger.handler.WithLevel(zerolog.Level(level)).Err(err).Msg(m)
-		} else {
-			_logger.handler.WithLevel(zerolog.Level(level)).Msg(m)
+		// Note: compiler complains when using variadic expansion "writers...", therefore convert to []io.Writer first
+		var export []io.Writer
+		for _, w := range l.writers {
+			export = append(export, &errorWriter{writer: w})
+		}
+		multi := zerolog.MultiLevelWriter(export...)
+		handler = zerolog.New(multi)
+	}
+	if _timestamp {
+		handler = handler.With().Timestamp().Logger()
+	}
+	handler = withBaseFields(handler)
+
+	// add the fields of the registered hooks
+	handler = handler.Hook(fieldHook{})
+
+	// sample all levels except fatal and panic, using new samplers to reset the counters
+	if _sampling > 1 {
+		n := uint32(_sampling)
+		handler = handler.Sample(zerolog.LevelSampler{
+			TraceSampler: &zerolog.BasicSampler{N: n},
+			DebugSampler: &zerolog.BasicSampler{N: n},
+			InfoSampler:  &zerolog.BasicSampler{N: n},
+			WarnSampler:  &zerolog.BasicSampler{N: n},
+			ErrorSampler: &zerolog.BasicSampler{N: n},
+		})
+	}
+
+	l.handler = &handler
+}
+
+// log is an internal function to redirect logging requests to either the handler or local buffer of the Logger. It
+// returns true if the message is written to the handler, and false if the message is buffered or dropped. Messages
+// dropped by sampling are considered to be written.
+func (l *Logger) log(level Level, fields []Field, msg string, err error, v ...interface{}) bool {
+	// skip messages below the minimum level of the instance or the global level
+	if level < l.level {
+		return false
+	}
+
+	// skip formatting altogether when logging is disabled
+	if GlobalLevel() == Disabled {
+		return false
+	}
+
+	m := formatMessage(msg, v)
+
+	if held, flush := l.holdMessage(level, fields, m, err); held {
+		if flush {
+			l.Flush()
 		}
+		return false
 	}
+
+	return l.emit(level, fields, m, err, nil)
+}
+
+// emit writes a log message to the handler of the Logger, unless it is dropped by the minimum level of the Logger, the
+// global level, or a rate limit. It returns true if the message is written. The tags are assigned when written if tags
+// is nil, see writeEvent.
+func (l *Logger) emit(level Level, fields []Field, msg string, err error, tags *logTags) bool {
+	if level < l.level || level < GlobalLevel() || !allow(level) {
+		return false
+	}
+	writeEvent(l.handler, level, fields, msg, err, tags)
+	return true
+}
+
+// holdMessage adds a log message to the buffer if the Logger is on hold. It returns true if the message is buffered,
+// and whether the buffer is to be flushed as the message is an error, see HoldUntilError.
+func (l *Logger) holdMessage(level Level, fields []Field, msg string, err error) (held bool, flush bool) {
+	// check the hold state using a shared lock first, as holding logs is the exception
+	l.mu.RLock()
+	hold := l.hold
+	l.mu.RUnlock()
+	if !hold {
+		return false, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.hold {
+		return false, false
+	}
+
+	var log Message
+	log.Level = level
+	log.Time = zerolog.TimestampFunc()
+	log.Message = msg
+	log.fields = fields
+	log.err = err
+	log.tags = newLogTags()
+	if err != nil {
+		log.Error = err.Error()
+	}
+	l.buffer = append(l.buffer, log)
+	if l.holdMax > 0 && len(l.buffer) > l.holdMax {
+		copy(l.buffer, l.buffer[1:])
+		l.buffer = l.buffer[:l.holdMax]
+	}
+	return true, l.untilError && level >= ErrorLevel
 }
 
 //======================================================================================================================
@@ -228,7 +647,8 @@ func log(level Level, msg string, err error, v ...interface{}) {
 // region Public Functions
 //======================================================================================================================
 
-// NewLogger initializes a new logger with the desired format.
+// NewLogger initializes a new logger with the desired format. The logger does not filter logs by itself, leaving the
+// filtering to the global level. Use NewLoggerWithLevel or SetLevel to set a minimum level for the logger.
 func NewLogger(format Format, noColor bool, writer ...Writer) *Logger {
 	var writers []Writer
 
@@ -243,39 +663,214 @@ func NewLogger(format Format, noColor bool, writer ...Writer) *Logger {
 		}
 	}
 
-	// init a zerologger with either a single writer or a multi-level writer
-	var l = new(Logger)
-	var handler zerolog.Logger
-	if len(writers) == 1 {
-		handler = zerolog.New(writers[0]).With().Timestamp().Logger()
-	} else {
-		// Note: compiler complains when using variadic expansion "writers...", therefore convert to []io.Writer first
-		var export []io.Writer
-		for _, w := range writers {
-			export = append(export, w)
-		}
-		multi := zerolog.MultiLevelWriter(export...)
-		handler = zerolog.New(multi).With().Timestamp().Logger()
-	}
-
 	// init the logger and return the reference
+	var l = new(Logger)
 	l.format = format
+	l.level = TraceLevel // defer filtering to the global level by default
 	l.writers = writers
 	l.noColor = noColor
-	l.handler = &handler
 	l.buffer = make([]Message, 0)
+	l.initHandler()
 
 	return l
 }
 
+// NewLoggerWithLevel initializes a new logger with the desired format and minimum level. Logs below the minimum level
+// are dropped by the logger, while logs that meet the minimum level are still subject to the global level. Use the
+// Disabled level to create a logger that discards all logs, for example when a library requires a logger.
+func NewLoggerWithLevel(format Format, level Level, noColor bool, writer ...Writer) *Logger {
+	l := NewLogger(format, noColor, writer...)
+	l.level = level
+	return l
+}
+
+// Close closes all writers of the Logger that implement io.Closer, such as an AsyncWriter or a syslog writer. It
+// continues when a writer fails to close and returns an error describing all failures. Logs held by HoldUntilError are
+// either flushed or discarded before the writers are closed.
+func (l *Logger) Close() error {
+	l.mu.RLock()
+	untilError := l.untilError
+	l.mu.RUnlock()
+	if untilError {
+		if l.flushOnClose {
+			l.Flush()
+		} else {
+			l.Discard()
+		}
+	}
+
+	var errs multiError
+	for _, w := range l.writers {
+		if c, ok := w.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Debug logs a debugging message using the Logger instance.
+func (l *Logger) Debug(msg string) {
+	l.log(DebugLevel, nil, msg, nil)
+}
+
+// DebugE logs a debugging error using the Logger instance.
+func (l *Logger) DebugE(e error, msg string) {
+	l.log(DebugLevel, nil, msg, e)
+}
+
+// DebugKV logs a debugging message with fields, specified as alternating key/value pairs, using the Logger instance.
+func (l *Logger) DebugKV(msg string, keyvals ...interface{}) {
+	l.log(DebugLevel, keyvalFields(keyvals), msg, nil)
+}
+
+// Debugf logs a formatted debugging message using the Logger instance.
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.log(DebugLevel, nil, format, nil, v...)
+}
+
+// Error logs an error message using the Logger instance.
+func (l *Logger) Error(msg string) {
+	l.log(ErrorLevel, nil, msg, nil)
+}
+
+// ErrorE logs an error using the Logger instance.
+func (l *Logger) ErrorE(e error, msg string) {
+	l.log(ErrorLevel, nil, msg, e)
+}
+
+// ErrorKV logs an error message with fields, specified as alternating key/value pairs, using the Logger instance.
+func (l *Logger) ErrorKV(msg string, keyvals ...interface{}) {
+	l.log(ErrorLevel, keyvalFields(keyvals), msg, nil)
+}
+
+// Errorf logs a formatted error message using the Logger instance.
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.log(ErrorLevel, nil, format, nil, v...)
+}
+
+// IfError logs an error message using the Logger instance if err is not nil. It is typically used to report errors
+// of deferred calls, such as defer l.IfError(f.Close(), "Cannot close file").
+func (l *Logger) IfError(err error, msg string) {
+	if err != nil {
+		l.log(ErrorLevel, nil, msg, err)
+	}
+}
+
+// Info logs a message using the Logger instance.
+func (l *Logger) Info(msg string) {
+	l.log(InfoLevel, nil, msg, nil)
+}
+
+// InfoE logs an error using the Logger instance.
+func (l *Logger) InfoE(e error, msg string) {
+	l.log(InfoLevel, nil, msg, e)
+}
+
+// InfoKV logs a message with fields, specified as alternating key/value pairs, using the Logger instance.
+func (l *Logger) InfoKV(msg string, keyvals ...interface{}) {
+	l.log(InfoLevel, keyvalFields(keyvals), msg, nil)
+}
+
+// Infof logs a formatted message using the Logger instance.
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.log(InfoLevel, nil, format, nil, v...)
+}
+
+// LevelEnabled returns true if messages of the given level are written by the Logger instance, considering both the
+// minimum level of the instance and the global level. Use LevelEnabled to skip expensive computations of messages that
+// would be dropped.
+func (l *Logger) LevelEnabled(level Level) bool {
+	return level >= l.level && level >= GlobalLevel()
+}
+
+// Msg logs a message at the desired level using the Logger instance.
+func (l *Logger) Msg(level Level, msg string) {
+	l.log(level, nil, msg, nil)
+}
+
+// MsgE logs an error at the desired level using the Logger instance.
+func (l *Logger) MsgE(level Level, e error, msg string) {
+	l.log(level, nil, msg, e)
+}
+
+// Msgf logs a formatted message at the desired level using the Logger instance.
+func (l *Logger) Msgf(level Level, format string, v ...interface{}) {
+	l.log(level, nil, format, nil, v...)
+}
+
+// SetLevel sets the minimum level of logs to display for the Logger instance. Both the instance level and the global
+// level apply, meaning the more restrictive of the two levels wins. By default, the instance level is TraceLevel, which
+// defers the filtering to the global level.
+func (l *Logger) SetLevel(level Level) {
+	l.level = level
+}
+
+// Trace logs a tracing message using the Logger instance.
+func (l *Logger) Trace(msg string) {
+	l.log(TraceLevel, nil, msg, nil)
+}
+
+// TraceE logs a tracing error using the Logger instance.
+func (l *Logger) TraceE(e error, msg string) {
+	l.log(TraceLevel, nil, msg, e)
+}
+
+// TraceKV logs a tracing message with fields, specified as alternating key/value pairs, using the Logger instance.
+func (l *Logger) TraceKV(msg string, keyvals ...interface{}) {
+	l.log(TraceLevel, keyvalFields(keyvals), msg, nil)
+}
+
+// Tracef logs a formatted tracing message using the Logger instance.
+func (l *Logger) Tracef(format string, v ...interface{}) {
+	l.log(TraceLevel, nil, format, nil, v...)
+}
+
+// Warn logs a warning using the Logger instance.
+func (l *Logger) Warn(msg string) {
+	l.log(WarnLevel, nil, msg, nil)
+}
+
+// WarnE logs an error as warning using the Logger instance.
+func (l *Logger) WarnE(e error, msg string) {
+	l.log(WarnLevel, nil, msg, e)
+}
+
+// WarnKV logs a warning message with fields, specified as alternating key/value pairs, using the Logger instance.
+func (l *Logger) WarnKV(msg string, keyvals ...interface{}) {
+	l.log(WarnLevel, keyvalFields(keyvals), msg, nil)
+}
+
+// Warnf logs a formatted warning using the Logger instance.
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.log(WarnLevel, nil, format, nil, v...)
+}
+
 // Write implements the io.Writer interface for Logger.
 func (l *Logger) Write(p []byte) (n int, err error) {
-	lines := strings.Split(string(p), "\n")
-	for _, line := range lines {
-		// skip empty lines when not using default logging format
-		if line != "" || Format(zerolog.GlobalLevel()) == Format(Default) {
-			l.handler.WithLevel(zerolog.Level(l.level)).Msg(line)
-		}
+	// write at the minimum level of the instance, but not lower than debug to keep the default behavior
+	level := l.level
+	if level < DebugLevel {
+		level = DebugLevel
+	}
+
+	// skip converting the input when the level is disabled
+	if level < GlobalLevel() {
+		return len(p), nil
+	}
+
+	// avoid splitting the input when it contains a single line
+	if bytes.IndexByte(p, '\n') < 0 {
+		l.writeLine(level, string(p))
+		return len(p), nil
+	}
+	for _, line := range strings.Split(string(p), "\n") {
+		l.writeLine(level, line)
 	}
 	return len(p), nil
 }
@@ -287,11 +882,28 @@ func (f Format) MarshalText() (text []byte, err error) {
 
 // String converts a typed log format to it's string representation.
 func (f Format) String() string {
-	if f < Default || f > JSON {
+	if f < Default || f > CSV {
 		return ""
 	}
 
-	return [...]string{"default", "pretty", "json"}[f]
+	return [...]string{"default", "pretty", "json", "logfmt", "jsonindent", "csv"}[f]
+}
+
+// UnmarshalText implements the TextUnmarshaler interface for Format, using ParseFormat. It enables decoding a Format
+// from configuration files, such as JSON or YAML.
+func (f *Format) UnmarshalText(text []byte) error {
+	format, err := ParseFormat(string(text))
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// Enabled returns true if the level is at least as severe as the minimum level, for example to filter logs in a custom
+// writer. It compares the levels by Severity, as such TraceLevel is the least severe level.
+func (l Level) Enabled(min Level) bool {
+	return l.Severity() >= min.Severity()
 }
 
 // MarshalText implements the TextMarshaler interface for Level.
@@ -299,91 +911,220 @@ func (l Level) MarshalText() (text []byte, err error) {
 	return []byte(l.String()), nil
 }
 
+// Severity returns the rank of the level, increasing with severity, from 0 for TraceLevel up to 6 for PanicLevel. Use
+// Severity to order levels without relying on the numbering of Level, which assigns -1 to TraceLevel. NoLevel and
+// Disabled rank above PanicLevel.
+func (l Level) Severity() int {
+	return int(l) - int(TraceLevel)
+}
+
 // String converts a typed log level to its string representation.
 func (l Level) String() string {
 	z := zerolog.Level(l)
 	return zerolog.Level.String(z)
 }
 
+// UnmarshalText implements the TextUnmarshaler interface for Level, using ParseLevelLenient. It enables decoding a
+// Level from configuration files, such as JSON or YAML.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevelLenient(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalJSON converts the message into JSON, using the same structure as the logs produced by zerolog. It uses the
+// field names configured by SetFieldNames and the time layout configured by SetTimeFormat. The error is omitted when
+// empty, and the timestamp is omitted when disabled by SetTimestamp.
+func (m Message) MarshalJSON() ([]byte, error) {
+	var t interface{}
+	switch _timeFormat {
+	case zerolog.TimeFormatUnix:
+		t = m.Time.Unix()
+	case zerolog.TimeFormatUnixMs:
+		t = m.Time.UnixNano() / int64(time.Millisecond)
+	case zerolog.TimeFormatUnixMicro:
+		t = m.Time.UnixNano() / int64(time.Microsecond)
+	case zerolog.TimeFormatUnixNano:
+		t = m.Time.UnixNano()
+	default:
+		t = m.Time.Format(_timeFormat)
+	}
+
+	// add the fields in the same order as zerolog
+	fields := []struct {
+		name  string
+		value interface{}
+	}{
+		{zerolog.LevelFieldName, m.Level.String()},
+		{zerolog.ErrorFieldName, m.Error},
+		{zerolog.TimestampFieldName, t},
+		{zerolog.MessageFieldName, m.Message},
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for _, f := range fields {
+		if f.name == zerolog.ErrorFieldName && m.Error == "" {
+			continue
+		}
+		if f.name == zerolog.TimestampFieldName && !_timestamp {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
 // AppendWriter appends a writer to the list of writers known by Logger. Logs are duplicated for each known writer.
 func AppendWriter(w Writer) {
 	writers := make([]Writer, len(_logger.writers))
 	copy(writers, _logger.writers)
 	writers = append(writers, w)
-	InitLoggerWithWriter(_logger.format, _logger.noColor, writers...)
+	initLogger(_logger.format, _logger.noColor, writers...)
+}
+
+// AppendWriterWithFormat appends a writer that keeps its own log format to the list of writers known by Logger. The
+// format f takes precedence over the format of the Logger, including any format set by SetFormatting, InitLogger, or
+// InitLoggerWithWriter. This allows, for example, rendering Pretty logs on the console while writing JSON logs to a
+// file. The color coding still follows the Logger. Use RemoveWriter to remove the writer.
+func AppendWriterWithFormat(w Writer, f Format) {
+	AppendWriter(&fixedFormatWriter{Writer: w, format: f})
 }
 
 // Bypass logs an info message using a default logging format, bypassing the current level and format. Use this
 // function to ensure custom logs are written as-is to the standardized logging stream(s). If multiple writers are
-// specified, the message is duplicated for all writers.
+// specified, the message is duplicated for all writers. Bypass leaves the level and format untouched, and is safe for
+// concurrent use. Custom writers receive the message as JSON-formatted info log, hooks and sampling do not apply.
 func Bypass(msg string) {
-	// back up the current level and format
-	level := zerolog.GlobalLevel()
-	format := _logger.format
-	noColor := _logger.noColor
+	p, err := Message{Level: InfoLevel, Time: zerolog.TimestampFunc(), Message: msg}.MarshalJSON()
+	if err != nil {
+		return
+	}
+	p = append(p, '\n')
 
-	// ensure to restore the logger when done
-	defer zerolog.SetGlobalLevel(level)
-	defer SetFormatting(format, noColor)
+	for _, w := range _logger.writers {
+		_, err := bypass(w, p)
+		handleWriteError(err)
+	}
+}
 
-	// log a info message with default format
-	SetFormatting(Default, true)
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	_logger.handler.Info().Msg(msg)
+// Close closes all writers of the global logger that implement io.Closer. Call Close before the program exits to
+// release file handles and network connections.
+func Close() error {
+	return _logger.Close()
+}
+
+// CurrentFormat retrieves the log format of the global logger, as set by InitLogger or SetFormatting.
+func CurrentFormat() Format {
+	return _logger.format
 }
 
 // Debug logs a debugging message.
 func Debug(msg string) {
-	log(DebugLevel, msg, nil)
+	_logger.log(DebugLevel, nil, msg, nil)
 }
 
 // DebugE logs a debugging error.
 func DebugE(e error, msg string) {
-	log(DebugLevel, msg, e)
+	_logger.log(DebugLevel, nil, msg, e)
+}
+
+// DebugKV logs a debugging message with fields, specified as alternating key/value pairs, see InfoKV.
+func DebugKV(msg string, keyvals ...interface{}) {
+	_logger.log(DebugLevel, keyvalFields(keyvals), msg, nil)
 }
 
 // Debugf logs a formatted debugging message.
 func Debugf(format string, v ...interface{}) {
-	log(DebugLevel, format, nil, v...)
+	_logger.log(DebugLevel, nil, format, nil, v...)
+}
+
+// Disable discards all logs by setting the global level to Disabled. Messages are dropped before they are formatted,
+// making disabled logging virtually free. Call Enable to restore the prior global level.
+func Disable() {
+	if GlobalLevel() != Disabled {
+		_enabledLevel = GlobalLevel()
+	}
+	SetGlobalLevel(Disabled)
+}
+
+// Enable restores the global level prior to calling Disable. It has no effect if logging is not disabled.
+func Enable() {
+	if GlobalLevel() == Disabled {
+		SetGlobalLevel(_enabledLevel)
+	}
 }
 
 // Error logs an error message.
 func Error(msg string) {
-	log(ErrorLevel, msg, nil)
+	_logger.log(ErrorLevel, nil, msg, nil)
 }
 
 // ErrorE logs an error.
 func ErrorE(e error, msg string) {
-	log(ErrorLevel, msg, e)
+	_logger.log(ErrorLevel, nil, msg, e)
+}
+
+// ErrorKV logs an error message with fields, specified as alternating key/value pairs, see InfoKV.
+func ErrorKV(msg string, keyvals ...interface{}) {
+	_logger.log(ErrorLevel, keyvalFields(keyvals), msg, nil)
 }
 
 // Errorf logs a formatted error message.
 func Errorf(format string, v ...interface{}) {
-	log(ErrorLevel, format, nil, v...)
+	_logger.log(ErrorLevel, nil, format, nil, v...)
 }
 
-// Fatal logs a fatal message. It exits the program with exit code 1. Fatal messages are never buffered.
+// Fatal logs a fatal message. It flushes all writers using Sync, runs the registered OnFatal callbacks, and exits the
+// program with the exit code set by SetFatalExitCode, which defaults to 1. Fatal messages are never buffered, but do
+// flush the logs held by HoldUntilError.
 func Fatal(msg string) {
-	_logger.handler.WithLevel(zerolog.FatalLevel).Msg(msg)
-	if !_suppressExit {
-		os.Exit(1)
-	}
+	FatalWithCode(_fatalExitCode, msg)
 }
 
-// FatalE logs a fatal error. It exits the program with exit code 1. Fatal messages are never buffered.
+// FatalE logs a fatal error. It flushes all writers using Sync, runs the registered OnFatal callbacks, and exits the
+// program with the exit code set by SetFatalExitCode, which defaults to 1. Fatal messages are never buffered, but do
+// flush the logs held by HoldUntilError.
 func FatalE(e error, msg string) {
-	_logger.handler.WithLevel(zerolog.FatalLevel).Err(e).Msg(msg)
-	if !_suppressExit {
-		os.Exit(1)
-	}
+	_logger.flushUntilError()
+	writeEvent(_logger.handler, FatalLevel, nil, msg, e, nil)
+	exit(_fatalExitCode)
 }
 
-// Fatalf logs a formatted fatal error. It exits the program with exit code 1. Fatal messages are never buffered.
+// FatalExitCode returns the exit code of Fatal logs, as set by SetFatalExitCode.
+func FatalExitCode() int {
+	return _fatalExitCode
+}
+
+// Fatalf logs a formatted fatal error. It flushes all writers using Sync, runs the registered OnFatal callbacks, and
+// exits the program with the exit code set by SetFatalExitCode, which defaults to 1. Fatal messages are never buffered,
+// but do flush the logs held by HoldUntilError.
 func Fatalf(format string, v ...interface{}) {
-	_logger.handler.WithLevel(zerolog.FatalLevel).Msgf(format, v...)
-	if !_suppressExit {
-		os.Exit(1)
-	}
+	_logger.flushUntilError()
+	writeEvent(_logger.handler, FatalLevel, nil, formatMessage(format, v), nil, nil)
+	exit(_fatalExitCode)
+}
+
+// FatalWithCode logs a fatal message, similar to Fatal, and exits the program with the given exit code. Use distinct
+// exit codes to signal the category of the failure to the calling process.
+func FatalWithCode(code int, msg string) {
+	_logger.flushUntilError()
+	writeEvent(_logger.handler, FatalLevel, nil, msg, nil, nil)
+	exit(code)
 }
 
 // GlobalLevel retrieves the logging level of all loggers.
@@ -391,68 +1132,111 @@ func GlobalLevel() Level {
 	return Level(zerolog.GlobalLevel())
 }
 
+// IfError logs an error message if err is not nil. It is typically used to report errors of deferred calls, such as
+// defer log.IfError(f.Close(), "Cannot close file").
+func IfError(err error, msg string) {
+	_logger.IfError(err, msg)
+}
+
 // Info logs a message.
 func Info(msg string) {
-	log(InfoLevel, msg, nil)
+	_logger.log(InfoLevel, nil, msg, nil)
 }
 
 // InfoE logs an error.
 func InfoE(e error, msg string) {
-	log(InfoLevel, msg, e)
+	_logger.log(InfoLevel, nil, msg, e)
+}
+
+// InfoKV logs a message with fields, specified as alternating key/value pairs, for example InfoKV("Created user",
+// "id", 42, "admin", true). Keys that are not strings are converted to strings. A trailing key without value is
+// logged with the value "!MISSING-VALUE".
+func InfoKV(msg string, keyvals ...interface{}) {
+	_logger.log(InfoLevel, keyvalFields(keyvals), msg, nil)
 }
 
 // Infof logs a formatted message.
 func Infof(format string, v ...interface{}) {
-	log(InfoLevel, format, nil, v...)
+	_logger.log(InfoLevel, nil, format, nil, v...)
 }
 
-// InitLogger initializes the global logger with the desired format. Output is written to STDOUT with color coding.
+// InitLogger initializes the global logger with the desired format. Output is written to STDOUT with color coding. If
+// LOG_DEFER_INIT is set, any logs held since the start of the program are written to STDOUT.
 func InitLogger(format Format) {
 	InitLoggerWithWriter(format, true)
 }
 
-// InitLoggerWithWriter initializes the global logger with the desired format, writer(s), and color coding.
+// InitLoggerWithWriter initializes the global logger with the desired format, writer(s), and color coding. If
+// LOG_DEFER_INIT is set, any logs held since the start of the program are written to the new writer(s).
 func InitLoggerWithWriter(format Format, noColor bool, writer ...Writer) {
-	b := _logger.buffer
-	_logger = NewLogger(format, noColor, writer...)
-	_logger.buffer = b
+	initLogger(format, noColor, writer...)
+	releaseInit()
+}
+
+// LevelEnabled returns true if messages of the given level are written by the active logger, considering both the
+// minimum level of the logger and the global level.
+func LevelEnabled(level Level) bool {
+	return _logger.LevelEnabled(level)
 }
 
 // Msg logs a message at the desired level.
 func Msg(level Level, msg string) {
-	log(level, msg, nil)
+	_logger.log(level, nil, msg, nil)
 }
 
 // MsgE logs an error at the desired level.
 func MsgE(level Level, e error, msg string) {
-	log(level, msg, e)
+	_logger.log(level, nil, msg, e)
 }
 
 // Msgf logs a formatted message at the desired level.
 func Msgf(level Level, format string, v ...interface{}) {
-	log(level, format, nil, v...)
+	_logger.log(level, nil, format, nil, v...)
+}
+
+// NoColor returns true if color coding is disabled for the global logger, as set by InitLogger or SetFormatting.
+func NoColor() bool {
+	return _logger.noColor
+}
+
+// OnFatal registers a callback to run before a Fatal log exits the program, for example to close files or network
+// connections. The callbacks run in reverse order of registration (LIFO), similar to deferred functions. Writers that
+// implement Flusher are flushed before the callbacks run.
+func OnFatal(f func()) {
+	_fatalHooks = append(_fatalHooks, f)
 }
 
 // ParseFormat converts a format string into a typed Format value. It returns an error if the input string does not
-// match known values.
+// match known values. The aliases "console", "text", and "human", as used by other logging libraries, map to Pretty
+// format, as it includes a timestamp and level for each log.
 func ParseFormat(formatStr string) (Format, error) {
 	switch strings.ToLower(formatStr) {
 	case "default":
 		return Format(Default), nil
 
-	case "pretty":
+	case "pretty", "console", "text", "human":
 		return Format(Pretty), nil
 
 	case "json":
 		return Format(JSON), nil
+
+	case "logfmt":
+		return Format(Logfmt), nil
+
+	case "jsonindent":
+		return Format(JSONIndent), nil
+
+	case "csv":
+		return Format(CSV), nil
 	}
 	return Format(Default), fmt.Errorf("unknown log format: '%s'", formatStr)
 }
 
-// ParseLevel converts a level string into a typed Level value. It returns an error if the input string does not
+// ParseLevel converts a level string into a typed Level value. The input is case-insensitive and surrounding
+// whitespace is ignored, such as "INFO" or " warn ". It returns InfoLevel and an error if the input string does not
 // match known values.
 func ParseLevel(levelStr string) (Level, error) {
-	l, e := zerolog.ParseLevel(levelStr)
+	l, e := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(levelStr)))
 	if e != nil {
 		return InfoLevel, e
 	}
@@ -460,14 +1244,87 @@ func ParseLevel(levelStr string) (Level, error) {
 	return Level(l), nil
 }
 
+// ParseLevelLenient converts a level string into a typed Level value, accepting case-insensitive names, common
+// abbreviations, and integer values. For example, "warn", "WARNING", "W", and "2" all convert to WarnLevel. Integer
+// values follow the numbering of Level, ranging from -1 (TraceLevel) to 7 (Disabled). It returns InfoLevel and an error
+// if the input string does not match known values.
+func ParseLevelLenient(levelStr string) (Level, error) {
+	s := strings.ToLower(strings.TrimSpace(levelStr))
+	switch s {
+	case "trace", "trc", "t":
+		return TraceLevel, nil
+	case "debug", "dbg", "d":
+		return DebugLevel, nil
+	case "info", "information", "inf", "i":
+		return InfoLevel, nil
+	case "warn", "warning", "wrn", "w":
+		return WarnLevel, nil
+	case "error", "err", "e":
+		return ErrorLevel, nil
+	case "fatal", "ftl", "f":
+		return FatalLevel, nil
+	case "panic", "pnc", "p":
+		return PanicLevel, nil
+	case "disabled", "off":
+		return Disabled, nil
+	}
+
+	if n, err := strconv.Atoi(s); err == nil && n >= int(TraceLevel) && n <= int(Disabled) {
+		return Level(n), nil
+	}
+
+	return InfoLevel, fmt.Errorf("unknown log level: '%s', want one of trace, debug, info, warn, error, fatal, panic, "+
+		"disabled, or an integer from -1 to 7", levelStr)
+}
+
 // RemoveWriter removes a writer from the list of writers known by Logger. The request is ignored when the writer cannot
 // be found.
 func RemoveWriter(w Writer) {
 	index := getWriterIndex(w)
 	if index >= 0 {
 		writers := append(_logger.writers[:index], _logger.writers[index+1:]...)
-		InitLoggerWithWriter(_logger.format, _logger.noColor, writers...)
+		initLogger(_logger.format, _logger.noColor, writers...)
+	}
+}
+
+// Sampling retrieves the sample rate of logs per level, as configured by SetSampling.
+func Sampling() int {
+	return _sampling
+}
+
+// SetBaseFields sets the fields added to each subsequent log, for example to add the name and version of a service.
+// Unlike AddHook, the fields are rendered once when the logger is initialized, which avoids the overhead of a callback
+// per log. Calling SetBaseFields again replaces the current fields, while an empty or nil map removes them. The fields
+// are kept when the format or writers change. The map is copied, subsequent changes to m have no effect.
+func SetBaseFields(m map[string]interface{}) {
+	var fields map[string]interface{}
+	if len(m) > 0 {
+		fields = make(map[string]interface{}, len(m))
+		for k, v := range m {
+			fields[k] = v
+		}
+	}
+	_baseFields = fields
+	_logger.initHandler()
+}
+
+// SetFieldNames overrides the names of the standard fields of JSON-formatted logs, for example to use "@timestamp",
+// "severity", and "msg" as expected by a log ingestion pipeline. Note that the names are applied to zerolog's global
+// settings, affecting other zerolog loggers too.
+func SetFieldNames(names FieldNames) {
+	if names.Timestamp == "" {
+		names.Timestamp = "time"
+	}
+	if names.Level == "" {
+		names.Level = "level"
+	}
+	if names.Message == "" {
+		names.Message = "message"
 	}
+
+	zerolog.TimestampFieldName = names.Timestamp
+	zerolog.LevelFieldName = names.Level
+	zerolog.MessageFieldName = names.Message
 }
 
 // SetFormatting adjusts the logging format of the current logger.
@@ -479,11 +1336,97 @@ func SetFormatting(format Format, noColor bool) {
 	}
 }
 
+// SetWriters replaces all writers known by Logger in a single step, applying the current format and color coding to
+// the new writers once. Unlike successive calls to AppendWriter and RemoveWriter, the logger is initialized only once
+// and never exposes an intermediate set of writers. Held messages are preserved, while the previous writers are left
+// untouched. If no writers are specified, a console writer to os.Stdout is used.
+func SetWriters(writers ...Writer) {
+	w := make([]Writer, len(writers))
+	copy(w, writers)
+	initLogger(_logger.format, _logger.noColor, w...)
+}
+
+// SetFatalExitCode sets the exit code of Fatal, FatalE, and Fatalf. The default exit code is 1.
+func SetFatalExitCode(code int) {
+	_fatalExitCode = code
+}
+
 // SetGlobalLevel sets the logging level for all loggers.
 func SetGlobalLevel(l Level) {
 	zerolog.SetGlobalLevel(zerolog.Level(l))
 }
 
+// SetOutput replaces all writers known by Logger with a single console writer to w, using the current format and
+// color coding, similar to the SetOutput function of the standard library. Held messages are preserved.
+func SetOutput(w io.Writer) {
+	initLogger(_logger.format, _logger.noColor, NewConsoleWriter(_logger.format, _logger.noColor, w))
+}
+
+// SetSampling logs the first message of each level and every nth message thereafter, suppressing repetitive logs. A
+// value of 1 or less disables sampling. Fatal messages are never sampled. The sample counters are reset when the logger
+// is reinitialized. Buffered messages count towards sampling only when flushed, as the sampler is applied when the
+// messages are actually written.
+func SetSampling(n int) {
+	_sampling = n
+	_logger.initHandler()
+}
+
+// SetTimestamp enables or disables the timestamp of logs in all formats. Disable timestamps when the logs are shipped
+// to a system that adds its own timestamp on ingestion. Timestamps are enabled by default.
+func SetTimestamp(enabled bool) {
+	_timestamp = enabled
+	_logger.initHandler()
+}
+
+// SetTimeFormat sets the layout of timestamps for all formats, for example "2006-01-02T15:04:05.000Z07:00" to include
+// milliseconds. The layout is also used by UnmarshalLog to parse timestamps. Use one of zerolog's Unix time formats,
+// such as "UNIXMS", to render timestamps as integers. Note that the layout is applied to zerolog's global time field
+// format, affecting other zerolog loggers too.
+func SetTimeFormat(layout string) {
+	_timeFormat = layout
+	zerolog.TimeFieldFormat = layout
+}
+
+// SetUTC enables or disables the rendering of timestamps in UTC for all formats, for example to compare logs across
+// hosts in different time zones. Timestamps use the local time zone by default. Note that the setting is applied to
+// zerolog's global time source, affecting other zerolog loggers too.
+func SetUTC(utc bool) {
+	if utc {
+		zerolog.TimestampFunc = func() time.Time { return time.Now().UTC() }
+	} else {
+		zerolog.TimestampFunc = time.Now
+	}
+}
+
+// Sync flushes all writers known by Logger that buffer logs internally, being writers that implement Flusher, such as
+// AsyncWriter and HTTPWriter. It continues when a writer fails to flush and returns an error describing all failures.
+// Unlike Flush, Sync does not write the logs held by Hold. Call Sync before the program exits, to ensure all logs
+// reach their destination.
+func Sync() error {
+	var errs multiError
+	for _, w := range _logger.writers {
+		if f, ok := w.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// TeeTo adds a destination that renders logs in its own format, without affecting the existing writers. It wraps w in a
+// console writer that keeps the format f, for example to write JSON logs to a file while keeping Pretty logs on the
+// console. The color coding follows the Logger. TeeTo returns the added writer, use RemoveWriter to remove it again.
+func TeeTo(f Format, w io.Writer) Writer {
+	c := NewConsoleWriter(f, _logger.noColor, w)
+	AppendWriterWithFormat(c, f)
+	return c
+}
+
 // UpdateWriter replaces an old writer from the list of writers known by Logger with a new writer. UpdateWriter returns
 // an error if the old writer cannot be found.
 func UpdateWriter(old Writer, new Writer) error {
@@ -494,32 +1437,66 @@ func UpdateWriter(old Writer, new Writer) error {
 
 	writers := _logger.writers
 	writers[index] = new
-	InitLoggerWithWriter(_logger.format, _logger.noColor, writers...)
+	initLogger(_logger.format, _logger.noColor, writers...)
 
 	return nil
 }
 
-// UnmarshalLog converts json bytes into a Message instance.
-func UnmarshalLog(bytes []byte) (*Message, error) {
-	const layout = "2006-01-02T15:04:05Z07:00"
+// Trace logs a tracing message.
+func Trace(msg string) {
+	_logger.log(TraceLevel, nil, msg, nil)
+}
 
-	// construct a placeholder with looser typing
-	raw := struct {
-		Level   string `json:"level"`
-		Time    string `json:"time"`
-		Message string `json:"message"`
-		Error   string `json:"error,omitempty"`
-	}{}
+// TraceE logs a tracing error.
+func TraceE(e error, msg string) {
+	_logger.log(TraceLevel, nil, msg, e)
+}
 
-	// convert json input to placeholder type
-	if err := json.Unmarshal(bytes, &raw); err != nil {
+// TraceKV logs a tracing message with fields, specified as alternating key/value pairs, see InfoKV.
+func TraceKV(msg string, keyvals ...interface{}) {
+	_logger.log(TraceLevel, keyvalFields(keyvals), msg, nil)
+}
+
+// Tracef logs a formatted tracing message.
+func Tracef(format string, v ...interface{}) {
+	_logger.log(TraceLevel, nil, format, nil, v...)
+}
+
+// UnmarshalLog converts json bytes into a Message instance. The timestamp is parsed using either RFC 3339 (with or
+// without nanoseconds) or an integer Unix timestamp in seconds or milliseconds. A missing timestamp results in a zero
+// time when timestamps are disabled by SetTimestamp, and in an error otherwise. UnmarshalLog uses the field names
+// configured by SetFieldNames.
+func UnmarshalLog(bytes []byte) (*Message, error) {
+	// construct a placeholder with looser typing, using the configured field names
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bytes, &fields); err != nil {
 		return nil, err
 	}
+	raw := struct {
+		Level   string
+		Message string
+		Error   string
+	}{}
+	targets := map[string]*string{
+		zerolog.LevelFieldName:   &raw.Level,
+		zerolog.MessageFieldName: &raw.Message,
+		zerolog.ErrorFieldName:   &raw.Error,
+	}
+	for name, target := range targets {
+		if v, ok := fields[name]; ok {
+			if err := json.Unmarshal(v, target); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	// convert input to typed timestamp, fail on error
-	timestamp, err := time.Parse(layout, raw.Time)
-	if err != nil {
-		return nil, fmt.Errorf("Cannot parse datetime format, got %s, want %s", raw.Time, layout)
+	// convert input to typed timestamp, fail on error, tolerate a missing timestamp when timestamps are disabled
+	var timestamp time.Time
+	if t, ok := fields[zerolog.TimestampFieldName]; ok || _timestamp {
+		var err error
+		if timestamp, err = parseTime(t); err != nil {
+			return nil, err
+		}
 	}
 
 	// parse Level
@@ -539,19 +1516,69 @@ func UnmarshalLog(bytes []byte) (*Message, error) {
 	return log, nil
 }
 
+// UnmarshalLogStream converts a stream of JSON-formatted log messages, one message per line, into Message instances.
+// Blank lines are skipped. Lines that cannot be parsed do not stop the conversion. Instead, UnmarshalLogStream returns
+// all successfully parsed messages, together with an error describing each invalid line and its line number.
+func UnmarshalLogStream(r io.Reader) ([]*Message, error) {
+	var messages []*Message
+	var errs multiError
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		m, err := UnmarshalLog(b)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %s", line, err))
+			continue
+		}
+		messages = append(messages, m)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("Cannot read log stream: %s", err))
+	}
+
+	if len(errs) > 0 {
+		return messages, errs
+	}
+	return messages, nil
+}
+
 // Warn logs a warning.
 func Warn(msg string) {
-	log(WarnLevel, msg, nil)
+	_logger.log(WarnLevel, nil, msg, nil)
 }
 
 // WarnE logs an error as warning.
 func WarnE(e error, msg string) {
-	log(WarnLevel, msg, e)
+	_logger.log(WarnLevel, nil, msg, e)
+}
+
+// WarnKV logs a warning message with fields, specified as alternating key/value pairs, see InfoKV.
+func WarnKV(msg string, keyvals ...interface{}) {
+	_logger.log(WarnLevel, keyvalFields(keyvals), msg, nil)
 }
 
 // Warnf logs a formatted warning.
 func Warnf(format string, v ...interface{}) {
-	log(WarnLevel, format, nil, v...)
+	_logger.log(WarnLevel, nil, format, nil, v...)
+}
+
+// Writers returns a copy of the list of writers known by Logger, for example to enumerate the destinations and to
+// remove some of them using RemoveWriter. Writers added by AppendWriterWithFormat or TeeTo are returned as-is, without
+// the wrapper that keeps their format fixed. Modifying the returned list does not affect the Logger.
+func Writers() []Writer {
+	writers := make([]Writer, len(_logger.writers))
+	for i, w := range _logger.writers {
+		if f, ok := w.(*fixedFormatWriter); ok {
+			w = f.Writer
+		}
+		writers[i] = w
+	}
+	return writers
 }
 
 //======================================================================================================================
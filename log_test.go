@@ -8,9 +8,19 @@ package log
 //======================================================================================================================
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,6 +45,14 @@ func TestInitLoggerWithWriter(t *testing.T) {
 	}
 	var tests = []test{
 		// default logger tests
+		{
+			msg:    "trace message",
+			msgf:   "%s message",
+			format: Default,
+			level:  TraceLevel,
+			result: "TRACE  trace message",
+			err:    "TRACE  trace message error=trace",
+		},
 		{
 			msg:    "debug message",
 			msgf:   "%s message",
@@ -77,6 +95,14 @@ func TestInitLoggerWithWriter(t *testing.T) {
 		},
 
 		// pretty logger tests
+		{
+			msg:    "trace message",
+			msgf:   "%s message",
+			format: Pretty,
+			level:  TraceLevel,
+			result: " | TRACE  | trace message",
+			err:    " | TRACE  | trace message error=trace",
+		},
 		{
 			msg:    "debug message",
 			msgf:   "%s message",
@@ -119,6 +145,13 @@ func TestInitLoggerWithWriter(t *testing.T) {
 		},
 
 		// // json logger tests
+		{
+			msg:    "trace message",
+			msgf:   "%s message",
+			format: JSON,
+			level:  TraceLevel,
+			err:    "trace",
+		},
 		{
 			msg:    "debug message",
 			msgf:   "%s message",
@@ -165,6 +198,14 @@ func TestInitLoggerWithWriter(t *testing.T) {
 		SetGlobalLevel(test.level)
 
 		switch test.level {
+		case TraceLevel:
+			Trace(test.msg)
+			Tracef(test.msgf, TraceLevel.String())
+			TraceE(errors.New(TraceLevel.String()), "trace message")
+			Msg(TraceLevel, test.msg)
+			Msgf(TraceLevel, test.msgf, TraceLevel.String())
+			MsgE(TraceLevel, errors.New(TraceLevel.String()), "trace message")
+
 		case DebugLevel:
 			Debug(test.msg)
 			Debugf(test.msgf, DebugLevel.String())
@@ -287,6 +328,14 @@ func TestParseFormat(t *testing.T) {
 		{input: "DEFAULT", expected: Default, err: ""},
 		{input: "PRETTY", expected: Pretty, err: ""},
 		{input: "JSON", expected: JSON, err: ""},
+		{input: "logfmt", expected: Logfmt, err: ""},
+		{input: "LOGFMT", expected: Logfmt, err: ""},
+		{input: "jsonindent", expected: JSONIndent, err: ""},
+		{input: "CSV", expected: CSV, err: ""},
+		{input: "console", expected: Pretty, err: ""},
+		{input: "text", expected: Pretty, err: ""},
+		{input: "TEXT", expected: Pretty, err: ""},
+		{input: "human", expected: Pretty, err: ""},
 		{input: "unknown", expected: Default, err: "unknown log format: 'unknown'"},
 	}
 
@@ -314,6 +363,14 @@ func TestLogFormatString(t *testing.T) {
 	assert.Equal(t, "default", Default.String())
 	assert.Equal(t, "pretty", Pretty.String())
 	assert.Equal(t, "json", JSON.String())
+	assert.Equal(t, "logfmt", Logfmt.String())
+	assert.Equal(t, "jsonindent", JSONIndent.String())
+	assert.Equal(t, "csv", CSV.String())
+	assert.Equal(t, "", Format(-1).String())
+
+	text, err := Logfmt.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t, "logfmt", string(text))
 }
 
 func TestWrite(t *testing.T) {
@@ -336,6 +393,1898 @@ func TestWrite(t *testing.T) {
 	assert.Equal(t, expected, []string(buffer))
 }
 
+func TestLoggerInstance(t *testing.T) {
+	// create two independent loggers with their own format and writer
+	w1 := NewBufferedWriter(JSON, true)
+	w2 := NewBufferedWriter(JSON, true)
+	l1 := NewLogger(JSON, true, w1)
+	l2 := NewLogger(Default, true, w2)
+	SetGlobalLevel(DebugLevel)
+
+	// log messages through each instance
+	l1.Info("audit message")
+	l1.Warnf("audit %s", "warning")
+	l2.ErrorE(errors.New("failure"), "app message")
+	l2.Msg(InfoLevel, "app info")
+
+	// test the log results of the first logger
+	got := w1.Buffer()
+	require.Len(t, got, 2)
+	m, e := UnmarshalLog([]byte(got[0]))
+	require.Nil(t, e)
+	assert.Equal(t, "audit message", m.Message)
+	assert.Equal(t, InfoLevel, m.Level)
+	m, e = UnmarshalLog([]byte(got[1]))
+	require.Nil(t, e)
+	assert.Equal(t, "audit warning", m.Message)
+	assert.Equal(t, WarnLevel, m.Level)
+
+	// test the log results of the second logger
+	got = w2.Buffer()
+	require.Len(t, got, 2)
+	assert.Contains(t, got[0], "ERROR  app message error=failure")
+	assert.Contains(t, got[1], "app info")
+
+	// test the instance buffer is independent of the global logger
+	l1.Hold()
+	l1.Debug("held message")
+	assert.Len(t, w1.Buffer(), 2)
+	assert.Len(t, _logger.buffer, 0)
+	l1.Flush()
+	assert.Len(t, w1.Buffer(), 3)
+
+	// restore the logger settings
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	// create a verbose and a quiet logger side by side
+	w1 := NewBufferedWriter(Default, true)
+	w2 := NewBufferedWriter(Default, true)
+	verbose := NewLogger(Default, true, w1)
+	quiet := NewLogger(Default, true, w2)
+	verbose.SetLevel(DebugLevel)
+	quiet.SetLevel(ErrorLevel)
+	SetGlobalLevel(DebugLevel)
+
+	// log the same messages to both loggers
+	for _, l := range []*Logger{verbose, quiet} {
+		l.Debug("debug message")
+		l.Info("info message")
+		l.Error("error message")
+	}
+	assert.Len(t, w1.Buffer(), 3)
+	require.Len(t, w2.Buffer(), 1)
+	assert.Contains(t, w2.Buffer()[0], "ERROR  error message")
+
+	// test the more restrictive global level wins
+	SetGlobalLevel(WarnLevel)
+	verbose.Debug("debug message")
+	verbose.Warn("warn message")
+	require.Len(t, w1.Buffer(), 4)
+	assert.Contains(t, w1.Buffer()[3], "WARN   warn message")
+
+	// restore the logger settings
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	w := NewBufferedWriter(Logfmt, true)
+	l := NewLogger(Logfmt, true, w)
+
+	// log a plain message and a message with an error
+	l.Info("Listing snapshots")
+	l.WarnE(errors.New("not found"), "snapshot=missing")
+
+	// test the rendered key/value pairs
+	got := w.Buffer()
+	require.Len(t, got, 2)
+	assert.Regexp(t, `^level=info msg="Listing snapshots" time=\S+$`, got[0])
+	assert.Regexp(t, `^level=warn msg="snapshot=missing" time=\S+ error="not found"$`, got[1])
+}
+
+func TestUnmarshalLogTimeLayouts(t *testing.T) {
+	type test struct {
+		name     string
+		time     string
+		expected time.Time
+		err      string
+	}
+
+	expected := time.Date(2020, 12, 17, 6, 12, 57, 0, time.UTC)
+	expectedNano := time.Date(2020, 12, 17, 6, 12, 57, 123456789, time.UTC)
+	expectedMillis := time.Date(2020, 12, 17, 6, 12, 57, 123000000, time.UTC)
+	var tests = []test{
+		{name: "RFC3339", time: `"2020-12-17T07:12:57+01:00"`, expected: expected},
+		{name: "RFC3339 UTC", time: `"2020-12-17T06:12:57Z"`, expected: expected},
+		{name: "RFC3339Nano", time: `"2020-12-17T06:12:57.123456789Z"`, expected: expectedNano},
+		{name: "Unix seconds", time: `1608185577`, expected: expected},
+		{name: "Unix seconds quoted", time: `"1608185577"`, expected: expected},
+		{name: "Unix millis", time: `1608185577123`, expected: expectedMillis},
+		{name: "Invalid string", time: `"yesterday"`, err: "Cannot parse datetime format, got \"yesterday\", want " +
+			"one of 2006-01-02T15:04:05Z07:00, 2006-01-02T15:04:05.999999999Z07:00, Unix seconds, Unix milliseconds"},
+		{name: "Invalid number", time: `1.5`, err: "Cannot parse datetime format, got 1.5"},
+	}
+
+	for _, test := range tests {
+		input := `{"level":"info","time":` + test.time + `,"message":"Listing snapshots"}`
+		m, e := UnmarshalLog([]byte(input))
+		if test.err != "" {
+			require.NotNil(t, e, test.name)
+			assert.Contains(t, e.Error(), test.err, test.name)
+			continue
+		}
+		require.Nil(t, e, test.name)
+		assert.True(t, test.expected.Equal(m.Time), test.name)
+		assert.Equal(t, "Listing snapshots", m.Message, test.name)
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	const layout = "2006-01-02T15:04:05.000Z07:00"
+	SetTimeFormat(layout)
+
+	// test the custom layout in JSON output and round-trip the message
+	w := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, w)
+	l.Info("json message")
+	got := w.Buffer()
+	require.Len(t, got, 1)
+	assert.Regexp(t, `"time":"\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}(Z|[+-]\d{2}:\d{2})"`, got[0])
+	m, e := UnmarshalLog([]byte(got[0]))
+	require.Nil(t, e)
+	assert.Equal(t, "json message", m.Message)
+
+	// test the custom layout in Pretty output
+	w = NewBufferedWriter(Pretty, true)
+	l = NewLogger(Pretty, true, w)
+	l.Info("pretty message")
+	got = w.Buffer()
+	require.Len(t, got, 1)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}(Z|[+-]\d{2}:\d{2}) \| INFO   \| pretty message$`, got[0])
+
+	// test Unix timestamps are parsed using the configured precision
+	SetTimeFormat(zerolog.TimeFormatUnixMs)
+	m, e = UnmarshalLog([]byte(`{"level":"info","time":1000,"message":"unix message"}`))
+	require.Nil(t, e)
+	assert.Equal(t, int64(1), m.Time.Unix())
+
+	// restore the logger settings
+	SetTimeFormat(time.RFC3339)
+}
+
+func TestSetSampling(t *testing.T) {
+	w := NewBufferedWriter(Default, true)
+	InitLoggerWithWriter(Default, true, w)
+	SetSampling(3)
+	assert.Equal(t, 3, Sampling())
+
+	// log the first message and every third message thereafter, per level
+	for i := 1; i <= 7; i++ {
+		Warnf("warning %d", i)
+		Errorf("error %d", i)
+	}
+	got := w.Buffer()
+	require.Len(t, got, 6)
+	assert.Contains(t, got[0], "warning 1")
+	assert.Contains(t, got[2], "warning 4")
+	assert.Contains(t, got[4], "warning 7")
+	assert.Contains(t, got[5], "error 7")
+
+	// test the counters are reset when the logger is reinitialized
+	w.Reset()
+	InitLoggerWithWriter(Default, true, w)
+	Warn("warning 1")
+	Warn("warning 2")
+	require.Len(t, w.Buffer(), 1)
+	assert.Contains(t, w.Buffer()[0], "warning 1")
+
+	// test fatal messages are never sampled
+	_suppressExit = true
+	Fatal("fatal 1")
+	Fatal("fatal 2")
+	assert.Len(t, w.Buffer(), 3)
+	_suppressExit = false
+
+	// restore the logger settings
+	SetSampling(0)
+	InitLogger(Default)
+}
+
+func TestBufferCapacity(t *testing.T) {
+	// fill a buffered writer past its capacity
+	w := NewBufferedWriterWithCapacity(Default, true, 3)
+	l := NewLogger(Default, true, w)
+	SetGlobalLevel(InfoLevel)
+	for i := 1; i <= 5; i++ {
+		l.Infof("message %d", i)
+	}
+
+	// test the oldest lines are evicted while order is preserved
+	got := w.Buffer()
+	require.Len(t, got, 3)
+	assert.Contains(t, got[0], "message 3")
+	assert.Contains(t, got[1], "message 4")
+	assert.Contains(t, got[2], "message 5")
+
+	// test the capacity survives a reset
+	w.Reset()
+	for i := 1; i <= 5; i++ {
+		l.Infof("message %d", i)
+	}
+	assert.Len(t, w.Buffer(), 3)
+
+	// fill the held buffer past its capacity
+	w.Reset()
+	l.SetHoldCapacity(2)
+	l.Hold()
+	for i := 1; i <= 4; i++ {
+		l.Warnf("held %d", i)
+	}
+	require.Len(t, l.buffer, 2)
+	assert.Equal(t, "held 3", l.buffer[0].Message)
+	assert.Equal(t, "held 4", l.buffer[1].Message)
+	l.Flush()
+	got = w.Buffer()
+	require.Len(t, got, 2)
+	assert.Contains(t, got[0], "held 3")
+	assert.Contains(t, got[1], "held 4")
+}
+
+func TestHeldMessages(t *testing.T) {
+	w := NewBufferedWriter(Default, true)
+	InitLoggerWithWriter(Default, true, w)
+
+	// inspect the held messages without flushing them
+	Hold()
+	Info("first message")
+	WarnE(errors.New("failure"), "second message")
+	assert.Equal(t, 2, HeldCount())
+	held := HeldMessages()
+	require.Len(t, held, 2)
+	assert.Equal(t, "first message", held[0].Message)
+	assert.Equal(t, WarnLevel, held[1].Level)
+	assert.Equal(t, "failure", held[1].Error)
+	assert.Len(t, w.Buffer(), 0)
+
+	// test the returned messages are a copy
+	held[0].Message = "changed"
+	assert.Equal(t, "first message", HeldMessages()[0].Message)
+
+	// discard the held messages without emitting them
+	Discard()
+	assert.Equal(t, 0, HeldCount())
+	Flush()
+	assert.Len(t, w.Buffer(), 0)
+
+	// restore the logger settings
+	InitLogger(Default)
+}
+
+func TestNewConsoleWriterNil(t *testing.T) {
+	// test the nil output is rejected
+	w, err := NewConsoleWriterE(Default, true, nil)
+	assert.Nil(t, w)
+	require.NotNil(t, err)
+	assert.Equal(t, "Cannot create console writer, output is nil", err.Error())
+
+	// test the nil output is replaced by STDOUT
+	w = NewConsoleWriter(Default, true, nil)
+	require.NotNil(t, w)
+	assert.Equal(t, os.Stdout, w.output)
+
+	// test a valid output is accepted
+	buffer := Buffer{}
+	w, err = NewConsoleWriterE(Default, true, &buffer)
+	require.Nil(t, err)
+	assert.Equal(t, &buffer, w.output)
+}
+
+func TestOnFatal(t *testing.T) {
+	w := NewBufferedWriter(Default, true)
+	InitLoggerWithWriter(Default, true, w)
+
+	// register several callbacks
+	var calls []int
+	OnFatal(func() { calls = append(calls, 1) })
+	OnFatal(func() { calls = append(calls, 2) })
+	OnFatal(func() { calls = append(calls, 3) })
+
+	// test the callbacks run in reverse order, even when the exit is suppressed
+	_suppressExit = true
+	Fatal("fatal message")
+	assert.Equal(t, []int{3, 2, 1}, calls)
+	require.Len(t, w.Buffer(), 1)
+	assert.Contains(t, w.Buffer()[0], "FATAL  fatal message")
+
+	// restore the logger settings
+	_suppressExit = false
+	_fatalHooks = nil
+	InitLogger(Default)
+}
+
+func TestSetFieldNames(t *testing.T) {
+	SetFieldNames(FieldNames{Timestamp: "@timestamp", Level: "severity", Message: "msg"})
+
+	// test the raw JSON keys
+	w := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, w)
+	l.WarnE(errors.New("failure"), "custom names")
+	got := w.Buffer()
+	require.Len(t, got, 1)
+	assert.Regexp(t, `^{"severity":"warn","error":"failure","@timestamp":"[^"]+","msg":"custom names"}$`, got[0])
+
+	// test the message can be round-tripped
+	m, e := UnmarshalLog([]byte(got[0]))
+	require.Nil(t, e)
+	assert.Equal(t, WarnLevel, m.Level)
+	assert.Equal(t, "custom names", m.Message)
+	assert.Equal(t, "failure", m.Error)
+	assert.False(t, m.Time.IsZero())
+
+	// test the console formats use the custom names too
+	w = NewBufferedWriter(Pretty, true)
+	l = NewLogger(Pretty, true, w)
+	l.Info("custom names")
+	require.Len(t, w.Buffer(), 1)
+	assert.Contains(t, w.Buffer()[0], "| INFO   | custom names")
+
+	// restore the default field names
+	SetFieldNames(FieldNames{})
+	assert.Equal(t, "time", zerolog.TimestampFieldName)
+	assert.Equal(t, "level", zerolog.LevelFieldName)
+	assert.Equal(t, "message", zerolog.MessageFieldName)
+}
+
+func TestSetLevelColors(t *testing.T) {
+	SetLevelColors(map[Level]int{WarnLevel: 33, FatalLevel: 7})
+
+	// test the custom colors are applied when color coding is enabled
+	w := NewBufferedWriter(Pretty, false)
+	l := NewLogger(Pretty, false, w)
+	l.Warn("warn message")
+	l.Error("error message")
+	got := w.Buffer()
+	require.Len(t, got, 2)
+	assert.Contains(t, got[0], "| \x1b[33mWARN\x1b[0m   | warn message")
+	assert.Contains(t, got[1], "| \x1b[31mERROR\x1b[0m  | error message")
+
+	// test the color codes are omitted when color coding is disabled
+	w = NewBufferedWriter(Pretty, true)
+	l = NewLogger(Pretty, true, w)
+	l.Warn("warn message")
+	got = w.Buffer()
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "| WARN   | warn message")
+	assert.NotContains(t, got[0], "\x1b[")
+
+	// restore the default colors
+	SetLevelColors(nil)
+	assert.Equal(t, colorRed, _levelColors[WarnLevel])
+}
+
+func TestParseLevel(t *testing.T) {
+	type test struct {
+		input    string
+		expected Level
+		err      bool
+	}
+
+	var tests = []test{
+		{input: "info", expected: InfoLevel},
+		{input: "INFO", expected: InfoLevel},
+		{input: "Debug", expected: DebugLevel},
+		{input: " warn ", expected: WarnLevel},
+		{input: "Error\n", expected: ErrorLevel},
+		{input: "WARNING", expected: InfoLevel, err: true},
+		{input: "loud", expected: InfoLevel, err: true},
+	}
+
+	for _, test := range tests {
+		r, e := ParseLevel(test.input)
+		assert.Equal(t, test.expected, r, test.input)
+		if test.err {
+			assert.NotNil(t, e, test.input)
+		} else {
+			assert.Nil(t, e, test.input)
+		}
+	}
+}
+
+func TestParseLevelLenient(t *testing.T) {
+	type test struct {
+		input    string
+		expected Level
+		err      bool
+	}
+
+	var tests = []test{
+		{input: "trace", expected: TraceLevel},
+		{input: "TRC", expected: TraceLevel},
+		{input: "debug", expected: DebugLevel},
+		{input: "D", expected: DebugLevel},
+		{input: "Info", expected: InfoLevel},
+		{input: "information", expected: InfoLevel},
+		{input: "warn", expected: WarnLevel},
+		{input: "WARNING", expected: WarnLevel},
+		{input: "W", expected: WarnLevel},
+		{input: " wrn ", expected: WarnLevel},
+		{input: "ERR", expected: ErrorLevel},
+		{input: "fatal", expected: FatalLevel},
+		{input: "panic", expected: PanicLevel},
+		{input: "off", expected: Disabled},
+		{input: "-1", expected: TraceLevel},
+		{input: "0", expected: DebugLevel},
+		{input: "3", expected: ErrorLevel},
+		{input: "7", expected: Disabled},
+		{input: "8", expected: InfoLevel, err: true},
+		{input: "-2", expected: InfoLevel, err: true},
+		{input: "verbose", expected: InfoLevel, err: true},
+		{input: "", expected: InfoLevel, err: true},
+	}
+
+	for _, test := range tests {
+		r, e := ParseLevelLenient(test.input)
+		assert.Equal(t, test.expected, r, test.input)
+		if test.err {
+			require.NotNil(t, e, test.input)
+			assert.Contains(t, e.Error(), "want one of trace, debug, info, warn, error, fatal, panic, disabled")
+		} else {
+			assert.Nil(t, e, test.input)
+		}
+	}
+}
+
+func TestTraceFiltering(t *testing.T) {
+	w := NewBufferedWriter(Default, true)
+	l := NewLogger(Default, true, w)
+
+	// test trace messages are dropped by the debug level
+	SetGlobalLevel(DebugLevel)
+	l.Trace("trace message")
+	l.Debug("debug message")
+	require.Len(t, w.Buffer(), 1)
+	assert.Contains(t, w.Buffer()[0], "DEBUG  debug message")
+
+	// test trace messages are shown by the trace level, unless the instance level is more restrictive
+	SetGlobalLevel(TraceLevel)
+	l.Tracef("trace %s", "message")
+	l.SetLevel(DebugLevel)
+	l.TraceE(errors.New("failure"), "trace message")
+	require.Len(t, w.Buffer(), 2)
+	assert.Contains(t, w.Buffer()[1], "TRACE  trace message")
+
+	// restore the logger settings
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestUnmarshalLogStream(t *testing.T) {
+	input := `{"level":"info","time":"2020-12-17T06:12:57Z","message":"Listing snapshots"}
+
+{"level":"info","time":"2020-12-17T06:12:58Z"
+{"level":"warn","time":"2020-12-17T06:12:59Z","message":"Snapshot missing","error":"not found"}
+{"level":"unknown","time":"2020-12-17T06:13:00Z","message":"Invalid level"}
+`
+	messages, err := UnmarshalLogStream(strings.NewReader(input))
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Listing snapshots", messages[0].Message)
+	assert.Equal(t, WarnLevel, messages[1].Level)
+	assert.Equal(t, "not found", messages[1].Error)
+
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "line 3: ")
+	assert.Contains(t, err.Error(), "; line 5: Cannot parse level: unknown")
+
+	// test a valid stream returns no error
+	messages, err = UnmarshalLogStream(strings.NewReader(input[:strings.Index(input, "\n")]))
+	assert.Nil(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func TestCurrentFormat(t *testing.T) {
+	InitLogger(JSON)
+	assert.Equal(t, JSON, CurrentFormat())
+
+	SetFormatting(Pretty, true)
+	assert.Equal(t, Pretty, CurrentFormat())
+	assert.True(t, NoColor())
+
+	SetFormatting(Default, false)
+	assert.Equal(t, Default, CurrentFormat())
+	assert.False(t, NoColor())
+
+	InitLogger(Default)
+	assert.True(t, NoColor())
+}
+
+func TestMessageMarshalJSON(t *testing.T) {
+	m := Message{
+		Level:   WarnLevel,
+		Time:    time.Date(2020, 12, 17, 6, 12, 57, 0, time.UTC),
+		Message: "Snapshot missing",
+		Error:   "not found",
+	}
+
+	// test the output matches the structure of zerolog
+	b, err := json.Marshal(m)
+	require.Nil(t, err)
+	assert.Equal(t, `{"level":"warn","error":"not found","time":"2020-12-17T06:12:57Z","message":"Snapshot missing"}`,
+		string(b))
+
+	// test the message round-trips
+	got, err := UnmarshalLog(b)
+	require.Nil(t, err)
+	assert.Equal(t, m, *got)
+
+	// test the error is omitted when empty
+	m.Error = ""
+	b, err = json.Marshal(m)
+	require.Nil(t, err)
+	assert.Equal(t, `{"level":"warn","time":"2020-12-17T06:12:57Z","message":"Snapshot missing"}`, string(b))
+	got, err = UnmarshalLog(b)
+	require.Nil(t, err)
+	assert.Equal(t, m, *got)
+
+	// test the message matches the output of zerolog
+	w := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(JSON, true, w)
+	SetGlobalLevel(InfoLevel)
+	ErrorE(errors.New("not found"), "Snapshot missing")
+	require.Len(t, w.Buffer(), 1)
+	got, err = UnmarshalLog([]byte(w.Buffer()[0]))
+	require.Nil(t, err)
+	b, err = json.Marshal(got)
+	require.Nil(t, err)
+	assert.Equal(t, w.Buffer()[0], string(b))
+	InitLogger(Default)
+}
+
+func TestAppendWriterWithFormatLevel(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewSplitFileWriter(dir, Default, map[Level]string{ErrorLevel: "error.log", TraceLevel: "app.log"})
+	require.Nil(t, err)
+	defer w.(io.Closer).Close()
+	InitLoggerWithWriter(Default, true, NewBufferedWriter(Default, true))
+	defer InitLogger(Default)
+	AppendWriterWithFormat(w, JSON)
+	SetGlobalLevel(InfoLevel)
+
+	// test the level is passed to a writer with a fixed format
+	Info("Listing snapshots")
+	Error("Cannot connect")
+	b, err := os.ReadFile(filepath.Join(dir, "error.log"))
+	require.Nil(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	require.Len(t, lines, 1)
+	assert.Regexp(t, `^{"level":"error",.*"message":"Cannot connect"}$`, lines[0])
+	b, err = os.ReadFile(filepath.Join(dir, "app.log"))
+	require.Nil(t, err)
+	assert.Equal(t, 2, strings.Count(string(b), "\n"))
+}
+
+func TestAppendWriterWithFormat(t *testing.T) {
+	console := NewBufferedWriter(Default, true)
+	file := NewBufferedWriter(Default, true)
+	InitLoggerWithWriter(Pretty, true, console)
+	AppendWriterWithFormat(file, JSON)
+	SetGlobalLevel(InfoLevel)
+
+	// test both writers render the same message differently
+	Info("Listing snapshots")
+	require.Len(t, console.Buffer(), 1)
+	require.Len(t, file.Buffer(), 1)
+	assert.Regexp(t, `^\S+ \| INFO   \| Listing snapshots$`, console.Buffer()[0])
+	assert.Regexp(t, `^{"level":"info","time":"\S+","message":"Listing snapshots"}$`, file.Buffer()[0])
+
+	// test the global format does not override the fixed format
+	SetFormatting(Default, true)
+	Info("Listing snapshots")
+	require.Len(t, file.Buffer(), 2)
+	assert.Equal(t, "Listing snapshots", console.Buffer()[1])
+	assert.Regexp(t, `^{"level":"info",`, file.Buffer()[1])
+
+	// test the writer can be removed
+	RemoveWriter(file)
+	Info("Listing snapshots")
+	assert.Len(t, console.Buffer(), 3)
+	assert.Len(t, file.Buffer(), 2)
+
+	InitLogger(Default)
+}
+
+func TestSetWriters(t *testing.T) {
+	w1 := NewBufferedWriter(Default, true)
+	w2 := NewBufferedWriter(Default, true)
+	w3 := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(Pretty, true, w1, w2)
+	SetGlobalLevel(InfoLevel)
+	Hold()
+	Info("Held message")
+
+	// test output goes to the new writer only, using the current format
+	SetWriters(w3)
+	Info("Listing snapshots")
+	assert.Len(t, w1.Buffer(), 0)
+	assert.Len(t, w2.Buffer(), 0)
+	require.Len(t, w3.Buffer(), 1)
+	assert.Regexp(t, `^\S+ \| INFO   \| Listing snapshots$`, w3.Buffer()[0])
+
+	// test held messages are preserved
+	Flush()
+	require.Len(t, w3.Buffer(), 2)
+	assert.Contains(t, w3.Buffer()[1], "Held message")
+
+	// test the previous writers are left untouched
+	w1.Reset()
+	w2.Reset()
+	Info("Listing snapshots")
+	assert.Len(t, w1.Buffer(), 0)
+	assert.Len(t, w2.Buffer(), 0)
+
+	InitLogger(Default)
+}
+
+// closeWriter defines a buffered writer that counts the number of times it is closed.
+type closeWriter struct {
+	*BufferedWriter
+	closed int
+	err    error
+}
+
+func (w *closeWriter) Close() error {
+	w.closed++
+	return w.err
+}
+
+func TestClose(t *testing.T) {
+	w1 := &closeWriter{BufferedWriter: NewBufferedWriter(Default, true)}
+	w2 := &closeWriter{BufferedWriter: NewBufferedWriter(Default, true)}
+	w3 := NewBufferedWriter(Default, true)
+	InitLoggerWithWriter(Default, true, w1, w3)
+	AppendWriterWithFormat(w2, JSON)
+
+	// test all closers are closed exactly once
+	require.Nil(t, Close())
+	assert.Equal(t, 1, w1.closed)
+	assert.Equal(t, 1, w2.closed)
+
+	// test errors are collected
+	w1.err = errors.New("disk full")
+	w2.err = errors.New("connection reset")
+	err := Close()
+	require.NotNil(t, err)
+	assert.Equal(t, "disk full; connection reset", err.Error())
+	assert.Equal(t, 2, w1.closed)
+	assert.Equal(t, 2, w2.closed)
+
+	InitLogger(Default)
+}
+
+func TestLinesAtLevel(t *testing.T) {
+	type test struct {
+		format  Format
+		noColor bool
+	}
+	var tests = []test{
+		{format: JSON, noColor: true},
+		{format: Pretty, noColor: true},
+		{format: Pretty, noColor: false},
+		{format: Logfmt, noColor: true},
+		{format: Default, noColor: true},
+	}
+
+	SetGlobalLevel(DebugLevel)
+	for _, test := range tests {
+		w := NewBufferedWriter(test.format, test.noColor)
+		InitLoggerWithWriter(test.format, test.noColor, w)
+		Debug("debug message")
+		Info("info message")
+		Warn("warn message")
+		Error("error message")
+
+		// test the lines are filtered by level
+		got := w.LinesAtLevel(WarnLevel)
+		require.Len(t, got, 2, test.format.String())
+		assert.Contains(t, got[0], "warn message", test.format.String())
+		assert.Contains(t, got[1], "error message", test.format.String())
+
+		got = w.LinesAtLevel(InfoLevel)
+		require.Len(t, got, 3, test.format.String())
+		assert.Contains(t, got[0], "info message", test.format.String())
+		assert.Len(t, w.LinesAtLevel(TraceLevel), 4, test.format.String())
+	}
+
+	InitLogger(Default)
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestLevelAtDefault(t *testing.T) {
+	w := newTestLogger(t, Default)
+	defer SetLevelLabels(nil)
+
+	// test info messages starting with a level name are not mistaken for a level label
+	Info("Warn users before removing snapshots")
+	Info("debug mode enabled")
+	Info("ERROR: not a label")
+	Warn("Snapshot is stale")
+	Error("")
+	levels := []Level{InfoLevel, InfoLevel, InfoLevel, WarnLevel, ErrorLevel}
+	require.Len(t, w.Buffer(), len(levels))
+	for i, lvl := range levels {
+		if got, ok := w.LevelAt(i); assert.True(t, ok, w.Buffer()) {
+			assert.Equal(t, lvl, got, w.Buffer())
+		}
+	}
+
+	// test custom labels are matched including their padding
+	w.Reset()
+	SetLevelLabels(map[Level]string{WarnLevel: "WARNING", ErrorLevel: "error"})
+	Warn("Snapshot is stale")
+	Info("WARNING: not a label")
+	Error("Cannot remove snapshot")
+	levels = []Level{WarnLevel, InfoLevel, ErrorLevel}
+	require.Len(t, w.Buffer(), len(levels))
+	for i, lvl := range levels {
+		if got, ok := w.LevelAt(i); assert.True(t, ok, w.Buffer()) {
+			assert.Equal(t, lvl, got, w.Buffer())
+		}
+	}
+}
+
+func TestBypassConcurrent(t *testing.T) {
+	const n = 50
+	w1 := &syncWriter{writer: NewBufferedWriter(Default, true)}
+	w2 := &syncWriter{writer: NewBufferedWriter(Default, true)}
+	InitLoggerWithWriter(Pretty, true, w1)
+	AppendWriterWithFormat(NewAsyncWriter(w2, 10), JSON)
+	SetGlobalLevel(ErrorLevel)
+
+	// test concurrent calls leave the format and level untouched
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Bypass("Direct message")
+		}()
+		go func() {
+			defer wg.Done()
+			Error("Cannot connect")
+		}()
+	}
+	wg.Wait()
+	require.Nil(t, Close())
+	assert.Equal(t, Pretty, CurrentFormat())
+	assert.Equal(t, ErrorLevel, GlobalLevel())
+
+	// test all writers render the direct messages as-is and the other messages using their format
+	for _, w := range []*syncWriter{w1, w2} {
+		got := w.Buffer()
+		require.Len(t, got, 2*n)
+		direct := 0
+		for _, line := range got {
+			if line == "Direct message" {
+				direct++
+			} else {
+				assert.NotContains(t, line, "Direct message")
+			}
+		}
+		assert.Equal(t, n, direct)
+	}
+
+	InitLogger(Default)
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestLoggerWriteEmptyLines(t *testing.T) {
+	type test struct {
+		format   Format
+		expected int
+	}
+	var tests = []test{
+		{format: Default, expected: 3},
+		{format: JSON, expected: 2},
+		{format: Pretty, expected: 2},
+	}
+
+	// test empty lines depend on the format, regardless of the global level
+	for _, level := range []Level{DebugLevel, InfoLevel, WarnLevel} {
+		SetGlobalLevel(level)
+		for _, test := range tests {
+			w := NewBufferedWriter(test.format, true)
+			l := NewLogger(test.format, true, w)
+			l.SetLevel(WarnLevel)
+			_, err := l.Write([]byte("first\n\nsecond"))
+			require.Nil(t, err)
+			count := 0
+			for _, line := range w.Buffer() {
+				if line != "" {
+					count++
+				}
+			}
+			assert.Equal(t, test.expected, count, "%s at %s", test.format, level)
+		}
+	}
+
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestSetBaseFields(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test the base fields are added to each log
+	SetBaseFields(map[string]interface{}{"service": "backup", "version": 2})
+	defer SetBaseFields(nil)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 1)
+	assert.Equal(t, `{"level":"info","service":"backup","version":2,"message":"Listing snapshots"}`, w.Buffer()[0])
+
+	// test the base fields survive a change of format
+	w.Reset()
+	SetFormatting(Default, true)
+	Info("Listing snapshots")
+	assert.Equal(t, Buffer{"Listing snapshots service=backup version=2"}, w.Buffer())
+
+	// test the base fields are replaced
+	w.Reset()
+	SetBaseFields(map[string]interface{}{"service": "restore"})
+	Info("Listing snapshots")
+	assert.Equal(t, Buffer{"Listing snapshots service=restore"}, w.Buffer())
+
+	// test the base fields are removed
+	w.Reset()
+	SetBaseFields(nil)
+	Info("Listing snapshots")
+	assert.Equal(t, Buffer{"Listing snapshots"}, w.Buffer())
+}
+
+func TestSetTimestamp(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetTimestamp(false)
+
+	// test the timestamp is omitted and tolerated when disabled
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 1)
+	assert.Equal(t, `{"level":"info","message":"Listing snapshots"}`, w.Buffer()[0])
+	m, err := UnmarshalLog([]byte(w.Buffer()[0]))
+	require.Nil(t, err)
+	assert.True(t, m.Time.IsZero())
+	b, err := json.Marshal(m)
+	require.Nil(t, err)
+	assert.Equal(t, w.Buffer()[0], string(b))
+
+	// test the timestamp is omitted in the other formats too
+	SetFormatting(Pretty, true)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 2)
+	assert.Equal(t, "| INFO   | Listing snapshots", w.Buffer()[1])
+
+	// test the timestamp is required when enabled
+	SetTimestamp(true)
+	SetFormatting(JSON, true)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 3)
+	assert.Contains(t, w.Buffer()[2], `"time":`)
+	_, err = UnmarshalLog([]byte(w.Buffer()[0]))
+	assert.NotNil(t, err)
+}
+
+func TestPreviewHeld(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+	Hold()
+	Debug("Filtered message")
+	With(Field{Key: "id", Value: 42}).Info("Listing snapshots")
+	WarnE(errors.New("not found"), "Snapshot missing")
+
+	// test the preview does not flush or clear the held messages
+	preview := PreviewHeld(Default)
+	assert.Len(t, w.Buffer(), 0)
+	assert.Equal(t, 3, HeldCount())
+
+	// test the preview matches the flushed output
+	Flush()
+	assert.Equal(t, []string{"Listing snapshots id=42", `WARN   Snapshot missing error="not found"`}, preview)
+	assert.Equal(t, preview, []string(w.Buffer()))
+
+	// test the preview renders the given format
+	Hold()
+	Info("Listing snapshots")
+	preview = PreviewHeld(JSON)
+	require.Len(t, preview, 1)
+	assert.Regexp(t, `^{"level":"info","time":"\S+","message":"Listing snapshots"}$`, preview[0])
+	Discard()
+	Flush()
+
+	// test the preview ignores sampling
+	SetSampling(2)
+	defer SetSampling(0)
+	Hold()
+	Info("Listing snapshots")
+	Info("Listing snapshots")
+	Info("Listing snapshots")
+	assert.Len(t, PreviewHeld(Default), 3)
+	assert.Len(t, PreviewHeld(Default), 3)
+	Discard()
+	Flush()
+}
+
+func TestNewLoggerWithLevel(t *testing.T) {
+	w := NewBufferedWriter(Default, true)
+	l := NewLoggerWithLevel(Default, WarnLevel, true, w)
+	SetGlobalLevel(TraceLevel)
+
+	// test messages below the level of the instance are dropped
+	l.Trace("trace message")
+	l.Debug("debug message")
+	l.Info("info message")
+	l.Warn("warn message")
+	l.Error("error message")
+	assert.Equal(t, Buffer{"WARN   warn message", "ERROR  error message"}, w.Buffer())
+
+	// test the global level still applies
+	w.Reset()
+	SetGlobalLevel(ErrorLevel)
+	l.Warn("warn message")
+	l.Error("error message")
+	assert.Equal(t, Buffer{"ERROR  error message"}, w.Buffer())
+
+	SetGlobalLevel(InfoLevel)
+}
+
+func TestJSONIndentFormat(t *testing.T) {
+	var out bytes.Buffer
+	InitLoggerWithWriter(JSONIndent, true, NewConsoleWriter(JSONIndent, true, &out))
+	SetGlobalLevel(InfoLevel)
+
+	// test the output is indented across multiple lines
+	With(Field{Key: "id", Value: 42}).WarnE(errors.New("not found"), "Snapshot missing")
+	assert.Regexp(t, `^{\n  "level": "warn",\n  "id": 42,\n  "error": "not found",\n  "time": "\S+",\n`+
+		`  "message": "Snapshot missing"\n}\n$`, out.String())
+
+	// test the indented output can be parsed
+	m, err := UnmarshalLog(out.Bytes())
+	require.Nil(t, err)
+	assert.Equal(t, WarnLevel, m.Level)
+	assert.Equal(t, "Snapshot missing", m.Message)
+	assert.Equal(t, "not found", m.Error)
+
+	InitLogger(Default)
+}
+
+func TestFlushN(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(DebugLevel)
+	SetFlushDiagnostics(true)
+	defer SetFlushDiagnostics(false)
+
+	// test the count excludes the debug message announcing the flush
+	Hold()
+	Info("first message")
+	Warn("second message")
+	Error("third message")
+	assert.Equal(t, 3, FlushN())
+	assert.Len(t, w.Buffer(), 4)
+
+	// test the count excludes logs dropped by the global level
+	Hold()
+	Debug("debug message")
+	Info("info message")
+	SetGlobalLevel(InfoLevel)
+	assert.Equal(t, 1, FlushN())
+
+	// test an empty buffer
+	assert.Equal(t, 0, FlushN())
+}
+
+func TestLevelEnabled(t *testing.T) {
+	newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test the global level
+	assert.False(t, LevelEnabled(DebugLevel))
+	assert.True(t, LevelEnabled(InfoLevel))
+	assert.True(t, LevelEnabled(ErrorLevel))
+
+	// test the level of the instance
+	l := NewLoggerWithLevel(Default, WarnLevel, true, NewBufferedWriter(Default, true))
+	assert.False(t, l.LevelEnabled(InfoLevel))
+	assert.True(t, l.LevelEnabled(WarnLevel))
+	SetGlobalLevel(ErrorLevel)
+	assert.False(t, l.LevelEnabled(WarnLevel))
+}
+
+func TestIfError(t *testing.T) {
+	w := newTestLogger(t, Default)
+
+	// test nil errors emit nothing
+	IfError(nil, "Cannot close file")
+	assert.Len(t, w.Buffer(), 0)
+
+	// test errors are logged
+	IfError(errors.New("file already closed"), "Cannot close file")
+	assert.Equal(t, Buffer{`ERROR  Cannot close file error="file already closed"`}, w.Buffer())
+}
+
+func TestSetLevelLabels(t *testing.T) {
+	w := newTestLogger(t, Pretty)
+	SetLevelLabels(map[Level]string{WarnLevel: "WARNING", ErrorLevel: "error"})
+	defer SetLevelLabels(nil)
+
+	// test the custom labels are rendered and padded to the longest label
+	Info("Listing snapshots")
+	Warn("Snapshot missing")
+	Error("Cannot connect")
+	require.Len(t, w.Buffer(), 3)
+	assert.Regexp(t, `^\S+ \| INFO     \| Listing snapshots$`, w.Buffer()[0])
+	assert.Regexp(t, `^\S+ \| WARNING  \| Snapshot missing$`, w.Buffer()[1])
+	assert.Regexp(t, `^\S+ \| error    \| Cannot connect$`, w.Buffer()[2])
+	if got, ok := w.LevelAt(1); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, WarnLevel, got, w.Buffer())
+	}
+	if got, ok := w.LevelAt(2); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, ErrorLevel, got, w.Buffer())
+	}
+
+	// test the custom labels in Default format
+	w.Reset()
+	SetFormatting(Default, true)
+	Info("Listing snapshots")
+	Warn("Snapshot missing")
+	assert.Equal(t, Buffer{"Listing snapshots", "WARNING  Snapshot missing"}, w.Buffer())
+	if got, ok := w.LevelAt(1); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, WarnLevel, got, w.Buffer())
+	}
+
+	// test the defaults are restored
+	SetLevelLabels(nil)
+	w.Reset()
+	Warn("Snapshot missing")
+	assert.Equal(t, Buffer{"WARN   Snapshot missing"}, w.Buffer())
+}
+
+func TestDisable(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(DebugLevel)
+
+	// test logs are discarded while disabled
+	Disable()
+	Disable()
+	Info("disabled")
+	assert.Equal(t, Disabled, GlobalLevel())
+	assert.Empty(t, w.Buffer())
+
+	// test the prior level is restored
+	Enable()
+	Debug("enabled")
+	assert.Equal(t, DebugLevel, GlobalLevel())
+	assert.Equal(t, Buffer{"DEBUG  enabled"}, w.Buffer())
+
+	// test a logger with a disabled level
+	var buf bytes.Buffer
+	l := NewLoggerWithLevel(JSON, Disabled, true, NewConsoleWriter(JSON, true, &buf))
+	l.Error("disabled")
+	assert.Empty(t, buf.String())
+
+	// test formatting is skipped when disabled
+	Disable()
+	allocs := testing.AllocsPerRun(100, func() { Infof("disabled %s", "message") })
+	assert.Zero(t, allocs)
+	Enable()
+}
+
+func BenchmarkDisabled(b *testing.B) {
+	level := GlobalLevel()
+	defer SetGlobalLevel(level)
+	Disable()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Infof("disabled %s", "message")
+	}
+}
+
+// newTestLogger installs a global logger that captures all logs in a BufferedWriter using the given format without
+// color coding, at TraceLevel. The previous logger, including its held messages, and global level are restored when the
+// test completes. It mirrors logtest.New, which cannot be imported by the tests of this package. Use testify to assert
+// the captured logs, see BufferedWriter.LevelAt.
+func newTestLogger(t testing.TB, format Format) *BufferedWriter {
+	t.Helper()
+
+	prev := _logger
+	level := GlobalLevel()
+	t.Cleanup(func() {
+		_logger = prev
+		SetFormatting(prev.format, prev.noColor)
+		SetGlobalLevel(level)
+	})
+
+	w := NewBufferedWriter(format, true)
+	_logger = NewLogger(format, true, w)
+	SetGlobalLevel(TraceLevel)
+
+	return w
+}
+
+// newBenchLogger installs a global logger that discards all logs using the given format. The previous logger and global
+// level are restored when the test or benchmark completes.
+func newBenchLogger(b testing.TB, format Format) *Logger {
+	prev := _logger
+	level := GlobalLevel()
+	b.Cleanup(func() {
+		_logger = prev
+		SetGlobalLevel(level)
+	})
+
+	_logger = NewLogger(format, true, NewConsoleWriter(format, true, io.Discard))
+	SetGlobalLevel(InfoLevel)
+	return _logger
+}
+
+func BenchmarkInfo(b *testing.B) {
+	newBenchLogger(b, JSON)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Info("Listing snapshots")
+	}
+}
+
+func BenchmarkInfof(b *testing.B) {
+	newBenchLogger(b, JSON)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Infof("Listing %d snapshots", i)
+	}
+}
+
+func BenchmarkJSONFormat(b *testing.B) {
+	newBenchLogger(b, JSON)
+	e := With(Field{Key: "id", Value: 42}, Field{Key: "name", Value: "daily"})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Warn("Snapshot missing")
+	}
+}
+
+func BenchmarkWrite(b *testing.B) {
+	l := newBenchLogger(b, JSON)
+	l.SetLevel(InfoLevel)
+	p := []byte("Listing snapshots")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Write(p) //nolint:errcheck // benchmark only
+	}
+}
+
+func TestAllocations(t *testing.T) {
+	l := newBenchLogger(t, JSON)
+	l.SetLevel(InfoLevel)
+	e := With(Field{Key: "id", Value: 42})
+	p := []byte("Listing snapshots")
+
+	// test the non-formatted paths do not allocate, while Write only allocates to convert the input
+	assert.Zero(t, testing.AllocsPerRun(100, func() { Info("Listing snapshots") }))
+	assert.Zero(t, testing.AllocsPerRun(100, func() { e.Warn("Snapshot missing") }))
+	assert.Zero(t, testing.AllocsPerRun(100, func() { Debug("Skipped message") }))
+	assert.Equal(t, 1.0, testing.AllocsPerRun(100, func() { l.Write(p) })) //nolint:errcheck // test only
+}
+
+func TestCSVFormat(t *testing.T) {
+	var out bytes.Buffer
+	InitLoggerWithWriter(CSV, true, NewConsoleWriter(CSV, true, &out))
+	SetGlobalLevel(InfoLevel)
+
+	// test the header is written once, followed by quoted values
+	Info(`Listing snapshots, "daily"`)
+	With(Field{Key: "id", Value: 42}).WarnE(errors.New("not found"), "Snapshot missing")
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "time,level,message,error", lines[0])
+	assert.Regexp(t, `^\S+,info,"Listing snapshots, ""daily""",$`, lines[1])
+	assert.Regexp(t, `^\S+,warn,Snapshot missing,not found$`, lines[2])
+
+	InitLogger(Default)
+}
+
+func TestHoldUntilError(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test no output until an error occurs
+	HoldUntilError(false)
+	Info("Listing snapshots")
+	Warn("Snapshot is stale")
+	assert.Empty(t, w.Buffer())
+	assert.Equal(t, 2, HeldCount())
+
+	// test the buffer and the error are written, followed by subsequent logs
+	Error("Cannot remove snapshot")
+	Info("Done")
+	assert.Equal(t, Buffer{"Listing snapshots", "WARN   Snapshot is stale", "ERROR  Cannot remove snapshot", "Done"},
+		w.Buffer())
+	assert.Zero(t, HeldCount())
+
+	// test the buffer is discarded on close without an error
+	w.Reset()
+	HoldUntilError(false)
+	Info("Listing snapshots")
+	require.Nil(t, Close())
+	assert.Empty(t, w.Buffer())
+	assert.Zero(t, HeldCount())
+
+	// test the buffer is flushed on close if requested
+	HoldUntilError(true)
+	Info("Listing snapshots")
+	require.Nil(t, Close())
+	assert.Equal(t, Buffer{"Listing snapshots"}, w.Buffer())
+}
+
+func TestHoldUntilErrorAppendWriter(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test the hold is kept when a writer is appended, and released by the error
+	HoldUntilError(true)
+	Info("Listing snapshots")
+	extra := NewBufferedWriter(Default, true)
+	AppendWriter(extra)
+	Info("Pruning snapshots")
+	assert.Empty(t, w.Buffer())
+	assert.Equal(t, 2, HeldCount())
+	Error("Cannot remove snapshot")
+	expected := Buffer{"Listing snapshots", "Pruning snapshots", "ERROR  Cannot remove snapshot"}
+	assert.Equal(t, expected, w.Buffer())
+	assert.Equal(t, expected, extra.Buffer())
+
+	// test flushOnClose is kept when a writer is appended
+	w.Reset()
+	HoldUntilError(true)
+	Info("Listing snapshots")
+	AppendWriter(NewBufferedWriter(Default, true))
+	require.Nil(t, Close())
+	assert.Equal(t, Buffer{"Listing snapshots"}, w.Buffer())
+}
+
+func TestUnmarshalText(t *testing.T) {
+	type config struct {
+		Level  Level
+		Format Format
+	}
+
+	// test a round trip through JSON
+	in := config{Level: WarnLevel, Format: Logfmt}
+	data, err := json.Marshal(in)
+	require.Nil(t, err)
+	assert.Equal(t, `{"Level":"warn","Format":"logfmt"}`, string(data))
+	var out config
+	require.Nil(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	// test lenient values
+	require.Nil(t, json.Unmarshal([]byte(`{"Level":"WARNING","Format":"JSON"}`), &out))
+	assert.Equal(t, config{Level: WarnLevel, Format: JSON}, out)
+
+	// test invalid values
+	assert.NotNil(t, json.Unmarshal([]byte(`{"Level":"loud"}`), &out))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"Format":"xml"}`), &out))
+}
+
+func TestBufferedWriterConcurrent(t *testing.T) {
+	w := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, w)
+
+	// test concurrent logging to a single buffered writer, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				l.Warnf("Message %d.%d", i, j)
+				w.Buffer()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, w.Buffer(), 1000)
+	assert.Len(t, w.LinesAtLevel(WarnLevel), 1000)
+
+	w.Reset()
+	assert.Empty(t, w.Buffer())
+}
+
+func TestNewBufferedWriterClean(t *testing.T) {
+	w := NewBufferedWriterClean(Pretty)
+	l := NewLogger(Pretty, false, w)
+
+	// test the buffer contains no escape codes while color coding is enabled
+	l.With(Field{Key: "id", Value: 42}).Warn("Snapshot missing")
+	lines := w.Buffer()
+	require.Len(t, lines, 1)
+	assert.NotContains(t, lines[0], "\x1b[")
+	assert.Regexp(t, `^\S+ \| WARN   \| Snapshot missing id=42$`, lines[0])
+	assert.Equal(t, lines, w.LinesAtLevel(WarnLevel))
+
+	// test color coding is applied without the clean option
+	w = NewBufferedWriter(Pretty, false)
+	l = NewLogger(Pretty, false, w)
+	l.Warn("Snapshot missing")
+	assert.Contains(t, w.Buffer()[0], "\x1b[")
+}
+
+func TestSetRelativeTime(t *testing.T) {
+	w := newTestLogger(t, Pretty)
+	defer SetRelativeTime(false)
+
+	// test the rendered timestamp shows the elapsed time
+	SetRelativeTime(true)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 1)
+	assert.Regexp(t, `^\+\d+\.\d{3}s \| INFO   \| Listing snapshots$`, w.Buffer()[0])
+
+	// test the wall clock time is restored
+	SetRelativeTime(false)
+	w.Reset()
+	Info("Listing snapshots")
+	assert.NotRegexp(t, `^\+`, w.Buffer()[0])
+}
+
+func TestSetUTC(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	defer SetUTC(false)
+
+	// test the emitted time is rendered in UTC
+	SetUTC(true)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 1)
+	assert.Regexp(t, `"time":"[^"]+Z"`, w.Buffer()[0])
+
+	// test the time can be parsed
+	m, err := UnmarshalLog([]byte(w.Buffer()[0]))
+	require.Nil(t, err)
+	assert.Equal(t, time.UTC, m.Time.Location())
+
+	// test Pretty format renders UTC too
+	w.Reset()
+	SetFormatting(Pretty, true)
+	Info("Listing snapshots")
+	assert.Regexp(t, `^\S+Z \| INFO`, w.Buffer()[0])
+}
+
+func TestNamed(t *testing.T) {
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	tests := []struct {
+		format   Format
+		expected Buffer
+	}{
+		{format: Default, expected: Buffer{"billing: Created invoice id=42", "WARN   shipping: Parcel delayed"}},
+		{format: Pretty, expected: Buffer{"| INFO   | [billing] Created invoice id=42",
+			"| WARN   | [shipping] Parcel delayed"}},
+		{format: JSON, expected: Buffer{`{"level":"info","component":"billing","id":42,"message":"Created invoice"}`,
+			`{"level":"warn","component":"shipping","message":"Parcel delayed"}`}},
+		{format: Logfmt, expected: Buffer{`level=info msg="Created invoice" component=billing id=42`,
+			`level=warn msg="Parcel delayed" component=shipping`}},
+	}
+
+	// test the component tag appears and named loggers do not interfere
+	for _, tc := range tests {
+		w := NewBufferedWriter(tc.format, true)
+		InitLoggerWithWriter(tc.format, true, w)
+		SetGlobalLevel(InfoLevel)
+		billing := Named("billing")
+		shipping := Named("shipping")
+		billing.With(Field{Key: "id", Value: 42}).Info("Created invoice")
+		billing.Debug("Hidden by the global level")
+		shipping.Warn("Parcel delayed")
+		assert.Equal(t, tc.expected, w.Buffer())
+	}
+
+	InitLogger(Default)
+}
+
+func TestFormatWithoutArguments(t *testing.T) {
+	w := newTestLogger(t, Default)
+	defer func() { _suppressExit = false }()
+	_suppressExit = true
+
+	// test a literal '%' is logged verbatim without arguments
+	Infof("50% done")
+	Warnf("100%d", []interface{}{}...)
+	With(Field{Key: "id", Value: 42}).Errorf("75% done")
+	Fatalf("99% done")
+	Infof("%d%% done", 50)
+	assert.Equal(t, Buffer{"50% done", "WARN   100%d", "ERROR  75% done id=42", "FATAL  99% done", "50% done"},
+		w.Buffer())
+}
+
+func TestKeyValues(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test even and odd argument counts in Default format
+	InfoKV("Created user", "id", 42, "admin", true)
+	WarnKV("Missing value", "id", 42, "name")
+	ErrorKV("Non-string key", 7, "seven")
+	DebugKV("No fields")
+	assert.Equal(t, Buffer{"Created user admin=true id=42", "WARN   Missing value id=42 name=!MISSING-VALUE",
+		"ERROR  Non-string key 7=seven", "DEBUG  No fields"}, w.Buffer())
+
+	// test the pairs become fields in JSON format
+	w.Reset()
+	SetFormatting(JSON, true)
+	TraceKV("Created user", "id", 42, "name")
+	assert.Equal(t, Buffer{`{"level":"trace","id":42,"name":"!MISSING-VALUE","message":"Created user"}`}, w.Buffer())
+
+	// test the Logger instance
+	b := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, b)
+	l.ErrorKV("Cannot connect", "host", "db")
+	assert.Equal(t, Buffer{`{"level":"error","host":"db","message":"Cannot connect"}`}, b.Buffer())
+}
+
+func TestFlushErrorString(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test a message with only the error string is flushed including the error
+	m, err := UnmarshalLog([]byte(`{"level":"warn","error":"not found","message":"Snapshot missing"}`))
+	require.NoError(t, err)
+	Hold()
+	_logger.buffer = append(_logger.buffer, *m)
+	Flush()
+	assert.Equal(t, Buffer{"WARN   Snapshot missing error=\"not found\""}, w.Buffer())
+
+	// test held messages are rendered in the format active at the time of flushing
+	w.Reset()
+	Hold()
+	WarnE(errors.New("not found"), "Snapshot missing")
+	SetFormatting(JSON, true)
+	Flush()
+	assert.Equal(t, Buffer{`{"level":"warn","error":"not found","message":"Snapshot missing"}`}, w.Buffer())
+}
+
+func TestSetOutput(t *testing.T) {
+	newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test held messages are preserved and written to the new output
+	Hold()
+	Info("held message")
+	var b bytes.Buffer
+	SetOutput(&b)
+	assert.Equal(t, 1, HeldCount())
+	Flush()
+	Warn("Cannot connect")
+	assert.Equal(t, "held message\nWARN   Cannot connect\n", b.String())
+
+	// test the current format is applied
+	SetFormatting(JSON, true)
+	b.Reset()
+	SetOutput(&b)
+	Info("json message")
+	assert.Equal(t, "{\"level\":\"info\",\"message\":\"json message\"}\n", b.String())
+}
+
+func TestFatalExitCode(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	defer func() { _suppressExit = false }()
+	defer SetFatalExitCode(1)
+	_suppressExit = true
+
+	// test the default exit code
+	assert.Equal(t, 1, FatalExitCode())
+	Fatal("Cannot start")
+	assert.Equal(t, 1, _exitCode)
+
+	// test the configured exit code
+	SetFatalExitCode(3)
+	assert.Equal(t, 3, FatalExitCode())
+	FatalE(errors.New("not found"), "Cannot read config")
+	assert.Equal(t, 3, _exitCode)
+	Fatalf("Cannot bind port %d", 80)
+	assert.Equal(t, 3, _exitCode)
+
+	// test the exit code of a single call
+	FatalWithCode(4, "Cannot connect")
+	assert.Equal(t, 4, _exitCode)
+	assert.Equal(t, 3, FatalExitCode())
+	assert.Len(t, w.Buffer(), 4)
+	assert.Equal(t, "FATAL  Cannot connect", w.Buffer()[3])
+}
+
+func TestSetPrettySeparator(t *testing.T) {
+	w := newTestLogger(t, Pretty)
+	SetGlobalLevel(InfoLevel)
+	SetPrettySeparator(":")
+	SetLevelWidth(8)
+	defer SetPrettySeparator("|")
+	defer SetLevelWidth(0)
+
+	// test the custom separator and width
+	Info("Listing snapshots")
+	Warn("Snapshot missing")
+	require.Len(t, w.Buffer(), 2)
+	assert.Regexp(t, `^\S+ : INFO     : Listing snapshots$`, w.Buffer()[0])
+	assert.Regexp(t, `^\S+ : WARN     : Snapshot missing$`, w.Buffer()[1])
+	if got, ok := w.LevelAt(1); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, WarnLevel, got, w.Buffer())
+	}
+
+	// test an empty separator
+	w.Reset()
+	SetPrettySeparator("")
+	Error("Cannot connect")
+	require.Len(t, w.Buffer(), 1)
+	assert.Regexp(t, `^\S+ ERROR    Cannot connect$`, w.Buffer()[0])
+	if got, ok := w.LevelAt(0); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, ErrorLevel, got, w.Buffer())
+	}
+
+	// test the width applies to Default format and the defaults are restored
+	w.Reset()
+	SetFormatting(Default, true)
+	Warn("Snapshot missing")
+	SetLevelWidth(0)
+	Warn("Snapshot missing")
+	assert.Equal(t, Buffer{"WARN     Snapshot missing", "WARN   Snapshot missing"}, w.Buffer())
+}
+
+func TestFlushConcurrent(t *testing.T) {
+	SetGlobalLevel(InfoLevel)
+	b := NewBufferedWriter(Default, true)
+	l := NewLogger(Default, true, b)
+
+	// test logs written while flushing are not lost, and both the buffered and live logs keep their order
+	l.Hold()
+	for i := 0; i < 100; i++ {
+		l.Infof("held %d", i)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.Infof("live %d", i)
+		}
+	}()
+	l.Flush()
+	wg.Wait()
+
+	lines := b.Buffer()
+	require.Len(t, lines, 200)
+	held, live := 0, 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "held") {
+			assert.Equal(t, fmt.Sprintf("held %d", held), line)
+			held++
+		} else {
+			assert.Equal(t, fmt.Sprintf("live %d", live), line)
+			live++
+		}
+	}
+}
+
+// reentrantWriter defines a writer that calls back into its Logger on each write, used to verify the Logger is not
+// locked while flushing.
+type reentrantWriter struct {
+	syncWriter
+	logger *Logger
+}
+
+func (w *reentrantWriter) Write(p []byte) (n int, err error) {
+	w.logger.HeldCount()
+	return w.syncWriter.Write(p)
+}
+
+func TestFlushReentrant(t *testing.T) {
+	SetGlobalLevel(InfoLevel)
+	w := &reentrantWriter{syncWriter: syncWriter{writer: NewBufferedWriter(Default, true)}}
+	w.logger = NewLogger(Default, true, w)
+
+	// test a writer may call the Logger while its buffer is flushed
+	w.logger.Hold()
+	w.logger.Info("Listing snapshots")
+	w.logger.Info("Removing snapshot")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.logger.Flush()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Flush did not complete")
+	}
+	assert.Equal(t, Buffer{"Listing snapshots", "Removing snapshot"}, w.Buffer())
+}
+
+func TestTeeTo(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test both writers receive the message in their own format
+	var b bytes.Buffer
+	tee := TeeTo(JSON, &b)
+	Warn("Snapshot missing")
+	assert.Equal(t, Buffer{"WARN   Snapshot missing"}, w.Buffer())
+	assert.Equal(t, "{\"level\":\"warn\",\"message\":\"Snapshot missing\"}\n", b.String())
+
+	// test the teed format is kept when changing the format of the logger
+	SetFormatting(Logfmt, true)
+	b.Reset()
+	Info("Listing snapshots")
+	assert.Equal(t, "{\"level\":\"info\",\"message\":\"Listing snapshots\"}\n", b.String())
+
+	// test the teed writer is removed
+	RemoveWriter(tee)
+	b.Reset()
+	Info("Listing snapshots")
+	assert.Empty(t, b.String())
+	assert.Len(t, w.Buffer(), 3)
+}
+
+func TestMultilineMessage(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test a message with embedded line feeds is captured as a single entry
+	With(Field{Key: "id", Value: 42}).Warn("Snapshot missing\nfirst\tdetail \"quoted\"\nsecond detail")
+	Info("Listing snapshots")
+	assert.Equal(t, Buffer{"WARN   Snapshot missing\nfirst\tdetail \"quoted\"\nsecond detail id=42",
+		"Listing snapshots"}, w.Buffer())
+	if got, ok := w.LevelAt(0); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, WarnLevel, got, w.Buffer())
+	}
+
+	// test the other formats
+	for _, format := range []Format{Pretty, JSON, Logfmt} {
+		w.Reset()
+		SetFormatting(format, true)
+		Warn("Snapshot missing\nsecond line")
+		Info("Listing snapshots")
+		assert.Len(t, w.Buffer(), 2, format.String())
+		assert.Contains(t, w.Buffer()[0], "Snapshot missing", format.String())
+	}
+
+	// test CSV format, which writes a header first
+	w.Reset()
+	SetFormatting(CSV, true)
+	Warn("Snapshot missing\nsecond line")
+	require.Len(t, w.Buffer(), 2)
+	assert.Equal(t, ",warn,\"Snapshot missing\nsecond line\",", w.Buffer()[1])
+}
+
+func TestLevelSeverity(t *testing.T) {
+	levels := []Level{TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel, PanicLevel}
+
+	// test the severity increases monotonically, starting at zero for trace
+	for i, level := range levels {
+		assert.Equal(t, i, level.Severity(), level.String())
+	}
+	assert.Greater(t, NoLevel.Severity(), PanicLevel.Severity())
+	assert.Greater(t, Disabled.Severity(), NoLevel.Severity())
+
+	// test each level is enabled for itself and less severe levels only
+	for i, level := range levels {
+		for j, min := range levels {
+			assert.Equal(t, i >= j, level.Enabled(min), "%s >= %s", level, min)
+		}
+	}
+}
+
+func TestSetFlushDiagnostics(t *testing.T) {
+	w := newTestLogger(t, Default)
+
+	// test the diagnostic message is absent by default
+	Hold()
+	Info("held message")
+	Flush()
+	assert.Equal(t, Buffer{"held message"}, w.Buffer())
+
+	// test the diagnostic message is present when enabled
+	w.Reset()
+	SetFlushDiagnostics(true)
+	defer SetFlushDiagnostics(false)
+	Hold()
+	Info("held message")
+	Flush()
+	assert.Equal(t, Buffer{"DEBUG  Flushing buffer with 1 log(s)", "held message"}, w.Buffer())
+}
+
+func TestWriters(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test the writers are returned, including writers with a fixed format
+	b1 := NewBufferedWriter(Default, true)
+	b2 := NewBufferedWriter(JSON, true)
+	AppendWriter(b1)
+	AppendWriterWithFormat(b2, JSON)
+	writers := Writers()
+	require.Len(t, writers, 3)
+	assert.Equal(t, []Writer{w, b1, b2}, writers)
+
+	// test modifying the returned list does not affect the logger
+	writers[0] = b1
+	writers = append(writers[:1], writers[2])
+	assert.Equal(t, []Writer{w, b1, b2}, Writers())
+
+	// test a returned writer can be removed
+	RemoveWriter(Writers()[2])
+	assert.Equal(t, []Writer{w, b1}, Writers())
+}
+
+func TestDefaultFormatColors(t *testing.T) {
+	SetGlobalLevel(InfoLevel)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test the level is colored when color coding is enabled, while info logs have no label
+	w := NewBufferedWriter(Default, false)
+	l := NewLogger(Default, false, w)
+	l.Info("Listing snapshots")
+	l.Warn("Snapshot missing")
+	l.Error("Cannot connect")
+	assert.Equal(t, Buffer{"Listing snapshots", "\x1b[31mWARN\x1b[0m   Snapshot missing",
+		"\x1b[31mERROR\x1b[0m  Cannot connect"}, w.Buffer())
+	assert.Equal(t, Buffer{"\x1b[31mWARN\x1b[0m   Snapshot missing", "\x1b[31mERROR\x1b[0m  Cannot connect"},
+		w.LinesAtLevel(WarnLevel))
+
+	// test the colors are absent when color coding is disabled
+	w = NewBufferedWriter(Default, true)
+	l = NewLogger(Default, true, w)
+	l.Warn("Snapshot missing")
+	assert.Equal(t, Buffer{"WARN   Snapshot missing"}, w.Buffer())
+}
+
+// syncedWriter defines a writer that buffers logs until flushed, optionally failing to flush.
+type syncedWriter struct {
+	pending []string
+	flushed []string
+	err     error
+}
+
+func (w *syncedWriter) SetFormatting(format Format, noColor bool) {}
+
+func (w *syncedWriter) Write(p []byte) (n int, err error) {
+	w.pending = append(w.pending, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+func (w *syncedWriter) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.flushed = append(w.flushed, w.pending...)
+	w.pending = nil
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+	f1 := &syncedWriter{}
+	f2 := &syncedWriter{}
+	AppendWriter(f1)
+	AppendWriterWithFormat(f2, JSON)
+
+	// test Sync flushes all writers implementing Flusher, including writers with a fixed format
+	Info("Listing snapshots")
+	assert.Empty(t, f1.flushed)
+	require.Nil(t, Sync())
+	require.Len(t, f1.flushed, 1)
+	assert.Contains(t, f1.flushed[0], `"message":"Listing snapshots"`)
+	assert.Empty(t, f1.pending)
+	assert.Len(t, f2.flushed, 1)
+	assert.Len(t, w.Buffer(), 1)
+
+	// test the errors are aggregated
+	f1.err = errors.New("disk full")
+	f2.err = errors.New("timeout")
+	assert.EqualError(t, Sync(), "disk full; timeout")
+}
+
+func TestFatalSync(t *testing.T) {
+	newTestLogger(t, Default)
+	defer func() { _suppressExit = false }()
+	defer func() { _fatalHooks = nil }()
+	_suppressExit = true
+	f := &syncedWriter{}
+	AppendWriter(f)
+
+	// test the fatal log is flushed before the callbacks run and the program exits
+	var flushed []string
+	OnFatal(func() { flushed = append(flushed, f.flushed...) })
+	Fatal("Cannot start")
+	require.Len(t, flushed, 1)
+	assert.Contains(t, flushed[0], `"message":"Cannot start"`)
+	assert.Empty(t, f.pending)
+
+	// test the other fatal functions flush the writers too
+	FatalE(errors.New("not found"), "Cannot read config")
+	Fatalf("Cannot bind port %d", 80)
+	FatalWithCode(4, "Cannot connect")
+	assert.Len(t, f.flushed, 4)
+	assert.Empty(t, f.pending)
+}
+
+// delayWriter defines a buffered writer that delays each write, used to verify queued logs are flushed.
+type delayWriter struct {
+	syncWriter
+	delay time.Duration
+}
+
+func (w *delayWriter) Write(p []byte) (n int, err error) {
+	time.Sleep(w.delay)
+	return w.syncWriter.Write(p)
+}
+
+func TestSyncWrapped(t *testing.T) {
+	type test struct {
+		name string
+		wrap func(w Writer) Writer
+	}
+	tests := []test{
+		{name: "redact", wrap: func(w Writer) Writer { return NewRedactingWriter(w) }},
+		{name: "dedup", wrap: func(w Writer) Writer { return NewDedupWriter(w, time.Hour) }},
+		{name: "counting", wrap: func(w Writer) Writer { return NewCountingWriter(w) }},
+		{name: "multiformat", wrap: func(w Writer) Writer { return NewMultiFormatWriter(w, JSON) }},
+	}
+
+	for _, tc := range tests {
+		d := &delayWriter{syncWriter: syncWriter{writer: NewBufferedWriter(JSON, true)}, delay: 10 * time.Millisecond}
+		a := NewAsyncWriter(d, 10)
+		InitLoggerWithWriter(JSON, true, tc.wrap(a))
+		SetGlobalLevel(InfoLevel)
+
+		// test Sync drains an async writer wrapped by another writer
+		Info("Listing snapshots")
+		Info("Listing snapshots")
+		require.Nil(t, Sync(), tc.name)
+		assert.Len(t, d.Buffer(), 2, tc.name)
+		require.Nil(t, a.Close())
+	}
+	InitLogger(Default)
+}
+
+func TestFatalSyncWrapped(t *testing.T) {
+	d := &delayWriter{syncWriter: syncWriter{writer: NewBufferedWriter(JSON, true)}, delay: 10 * time.Millisecond}
+	a := NewAsyncWriter(d, 10)
+	InitLoggerWithWriter(JSON, true, NewRedactingWriter(NewCountingWriter(a)))
+	defer InitLogger(Default)
+	defer func() { _suppressExit = false }()
+	_suppressExit = true
+
+	// test the fatal log is flushed through the wrapping writers before the program exits
+	Fatal("Cannot start")
+	require.Len(t, d.Buffer(), 1)
+	assert.Contains(t, d.Buffer()[0], `"message":"Cannot start"`)
+	require.Nil(t, a.Close())
+}
+
 //======================================================================================================================
 // endregion
 //======================================================================================================================
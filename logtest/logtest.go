@@ -0,0 +1,86 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+// Package logtest provides a test logger that captures the logs of the global logger, together with helpers to verify
+// the captured logs.
+package logtest
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"strings"
+	"testing"
+
+	"go.markdumay.org/log"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// TestLogger captures the logs of the global logger in a BufferedWriter, see New. The assertion helpers report
+// failures to the test that created the TestLogger.
+type TestLogger struct {
+	*log.BufferedWriter
+	t testing.TB
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// New installs a global logger that captures all logs in a BufferedWriter using the given format without color
+// coding. The global level is set to TraceLevel to capture logs of all levels. A cleanup function registered with t
+// restores the previous format, color coding, writers, and global level, see log.Snapshot. Use the assertion helpers
+// of the returned TestLogger to verify the captured logs.
+func New(t testing.TB, format log.Format) *TestLogger {
+	t.Helper()
+
+	snapshot := log.Snapshot()
+	t.Cleanup(snapshot.Restore)
+
+	w := log.NewBufferedWriter(format, true)
+	log.InitLoggerWithWriter(format, true, w)
+	log.SetGlobalLevel(log.TraceLevel)
+
+	return &TestLogger{BufferedWriter: w, t: t}
+}
+
+// AssertContains reports a test failure if none of the captured logs contain sub.
+func (l *TestLogger) AssertContains(sub string) {
+	l.t.Helper()
+	for _, line := range l.Buffer() {
+		if strings.Contains(line, sub) {
+			return
+		}
+	}
+	l.t.Errorf("Expected logs to contain %q, got %q", sub, l.Buffer())
+}
+
+// AssertLevel reports a test failure if the captured log at index i does not have the level lvl. The level is parsed
+// similar to LinesAtLevel, as such logs in Default format without level indicator are considered to be Info logs.
+func (l *TestLogger) AssertLevel(i int, lvl log.Level) {
+	l.t.Helper()
+	lines := l.Buffer()
+	if i < 0 || i >= len(lines) {
+		l.t.Errorf("Expected log at index %d, got %d log(s)", i, len(lines))
+		return
+	}
+	if got, ok := l.LevelAt(i); !ok || got != lvl {
+		l.t.Errorf("Expected log %q to have level %s", lines[i], lvl)
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,94 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package logtest
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.markdumay.org/log"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+// recorder defines a testing.TB that records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestNew(t *testing.T) {
+	prev := log.NewBufferedWriter(log.Default, true)
+	log.InitLoggerWithWriter(log.Pretty, false, prev)
+	log.SetGlobalLevel(log.WarnLevel)
+
+	t.Run("capture", func(t *testing.T) {
+		r := &recorder{TB: t}
+		w := New(r, log.JSON)
+		log.Debug("Listing snapshots")
+		log.Warn("Snapshot missing")
+
+		// test the logs are captured with the assertion helpers
+		require.Len(t, w.Buffer(), 2)
+		w.AssertContains("Snapshot missing")
+		w.AssertLevel(0, log.DebugLevel)
+		w.AssertLevel(1, log.WarnLevel)
+		assert.Len(t, r.errors, 0)
+
+		// test the assertion helpers report failures
+		w.AssertContains("Snapshot found")
+		w.AssertLevel(0, log.InfoLevel)
+		w.AssertLevel(2, log.InfoLevel)
+		require.Len(t, r.errors, 3)
+		assert.Contains(t, r.errors[0], `Expected logs to contain "Snapshot found"`)
+		assert.Contains(t, r.errors[1], "to have level info")
+		assert.Equal(t, "Expected log at index 2, got 2 log(s)", r.errors[2])
+	})
+
+	t.Run("default", func(t *testing.T) {
+		r := &recorder{TB: t}
+		w := New(r, log.Default)
+		log.Info("Warn users before removing snapshots")
+		log.Info("debug mode enabled")
+		log.Warn("Snapshot missing")
+
+		// test info messages starting with a level name are not mistaken for a level label
+		w.AssertLevel(0, log.InfoLevel)
+		w.AssertLevel(1, log.InfoLevel)
+		w.AssertLevel(2, log.WarnLevel)
+		assert.Len(t, r.errors, 0)
+	})
+
+	// test the previous logger is restored
+	assert.Equal(t, log.Pretty, log.CurrentFormat())
+	assert.False(t, log.NoColor())
+	assert.Equal(t, log.WarnLevel, log.GlobalLevel())
+	assert.Len(t, prev.Buffer(), 0)
+	log.Warn("Snapshot missing")
+	require.Len(t, prev.Buffer(), 1)
+	assert.Contains(t, prev.Buffer()[0], "WARN")
+
+	log.InitLogger(log.Default)
+	log.SetGlobalLevel(log.InfoLevel)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,125 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// multiFormatWriter implements a log writer that renders each log in multiple formats to the same output.
+type multiFormatWriter struct {
+	out     io.Writer
+	formats []Format
+	writers []io.Writer  // renders the log to buf, one writer per format
+	buf     bytes.Buffer // collects the rendered lines of a single log
+	noColor bool
+	mu      sync.Mutex
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// initWriters creates a writer for each format, using the current color coding. The writers keep their state across
+// logs, such as the header of CSV format.
+func (w *multiFormatWriter) initWriters() {
+	w.writers = make([]io.Writer, len(w.formats))
+	for i, f := range w.formats {
+		w.writers[i] = newWriter(f, w.noColor, &w.buf)
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewMultiFormatWriter creates a log writer that renders each log once per format to out, in the order of the formats.
+// For example, the formats Pretty and JSON write a human-readable line followed by the raw JSON of the same log. As
+// zerolog passes each log to its writers as JSON, the writer intercepts the JSON and renders it separately for each
+// format. All lines of a log are written before the next log, even when logging concurrently. The formats are fixed,
+// while the color coding follows the Logger.
+func NewMultiFormatWriter(out io.Writer, formats ...Format) Writer {
+	f := make([]Format, len(formats))
+	copy(f, formats)
+	w := &multiFormatWriter{out: out, formats: f}
+	w.initWriters()
+	return w
+}
+
+// Flush flushes the output of the multiFormatWriter, if it implements Flusher.
+func (w *multiFormatWriter) Flush() error {
+	return flush(w.out)
+}
+
+// FlushContext flushes the output of the multiFormatWriter until the context is done, see FlushWithTimeout.
+func (w *multiFormatWriter) FlushContext(ctx context.Context) error {
+	return flushWriter(ctx, w.out)
+}
+
+// SetFormatting updates the color coding of an existing multiFormatWriter. The formats are fixed.
+func (w *multiFormatWriter) SetFormatting(format Format, noColor bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if noColor != w.noColor || w.writers == nil {
+		w.noColor = noColor
+		w.initWriters()
+	}
+}
+
+// Write implements the io.Writer interface for multiFormatWriter. It expects a single JSON-formatted log message.
+func (w *multiFormatWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// render all formats first to write the log in a single call
+	w.buf.Reset()
+	for _, fw := range w.writers {
+		if _, err := fw.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := w.buf.WriteTo(w.out); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// writeBypass writes the log once in Default format, instead of once per format. The log is passed to the bypass path
+// of the output if supported, otherwise it is rendered without color coding.
+func (w *multiFormatWriter) writeBypass(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if b, ok := w.out.(bypassWriter); ok {
+		return b.writeBypass(p)
+	}
+	return newWriter(Default, true, w.out).Write(p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,84 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestNewMultiFormatWriter(t *testing.T) {
+	var out bytes.Buffer
+	InitLoggerWithWriter(Default, true, NewMultiFormatWriter(&out, Pretty, JSON))
+	SetGlobalLevel(InfoLevel)
+
+	// test both representations are written for a single log
+	Warn("Snapshot missing")
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^\S+ \| WARN   \| Snapshot missing$`, lines[0])
+	assert.Regexp(t, `^{"level":"warn","time":"\S+","message":"Snapshot missing"}$`, lines[1])
+
+	// test the formats are fixed
+	out.Reset()
+	SetFormatting(Logfmt, true)
+	Warn("Snapshot missing")
+	lines = strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "| WARN   |")
+	assert.Contains(t, lines[1], `"level":"warn"`)
+
+	InitLogger(Default)
+}
+
+func TestMultiFormatWriterCSV(t *testing.T) {
+	var out bytes.Buffer
+	InitLoggerWithWriter(Default, true, NewMultiFormatWriter(&out, CSV))
+	defer InitLogger(Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test the header is written once
+	Info("Listing snapshots")
+	Warn("Snapshot missing")
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "time,level,message,error", lines[0])
+	assert.Contains(t, lines[2], "Snapshot missing")
+}
+
+func TestMultiFormatWriterBypass(t *testing.T) {
+	var out bytes.Buffer
+	InitLoggerWithWriter(Default, true, NewMultiFormatWriter(&out, Pretty, JSON))
+	defer InitLogger(Default)
+
+	// test the log is written once in Default format
+	Bypass("Custom message")
+	assert.Equal(t, "Custom message\n", out.String())
+
+	// test the bypass path of the output is used if supported
+	b := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(Default, true, NewMultiFormatWriter(b, Pretty, JSON))
+	Bypass("Custom message")
+	assert.Equal(t, Buffer{"Custom message"}, b.Buffer())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
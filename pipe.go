@@ -0,0 +1,103 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// pipeWriter implements an io.Writer that logs each written line at a fixed level with a fixed prefix. Partial lines
+// are buffered until the line is completed by a subsequent write.
+type pipeWriter struct {
+	level   Level
+	prefix  string
+	partial []byte
+	mu      sync.Mutex
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// emit logs a single line using the active global logger, skipping empty lines.
+func (w *pipeWriter) emit(line []byte) {
+	s := strings.TrimRight(string(line), "\r")
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	if w.prefix != "" {
+		s = w.prefix + " " + s
+	}
+	_logger.log(w.level, nil, s, nil)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewPipeWriter creates a writer that logs each written line at the given level using the active global logger, for
+// example to capture the stderr output of a subprocess. A non-empty prefix, such as "[subproc]", is prepended to each
+// line, separated by a space. Lines split across multiple writes are reassembled before they are logged. Empty lines
+// are skipped. The writer implements io.Closer to log any remaining partial line.
+func NewPipeWriter(level Level, prefix string) io.Writer {
+	return &pipeWriter{level: level, prefix: prefix}
+}
+
+// Close logs the remaining partial line, if any.
+func (w *pipeWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.emit(w.partial)
+	w.partial = nil
+	return nil
+}
+
+// Write implements the io.Writer interface for pipeWriter.
+func (w *pipeWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+
+	// release the consumed lines
+	if len(w.partial) == 0 {
+		w.partial = nil
+	}
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
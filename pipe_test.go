@@ -0,0 +1,56 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestNewPipeWriter(t *testing.T) {
+	w := newTestLogger(t, Default)
+	p := NewPipeWriter(ErrorLevel, "[subproc]")
+
+	// test fragments are reassembled into lines
+	fragments := []string{"Cannot con", "nect\nRetry", "ing in 5s\r\n\n", "Giving ", "up"}
+	for _, f := range fragments {
+		n, err := p.Write([]byte(f))
+		require.Nil(t, err)
+		assert.Equal(t, len(f), n)
+	}
+	assert.Equal(t, Buffer{"ERROR  [subproc] Cannot connect", "ERROR  [subproc] Retrying in 5s"}, w.Buffer())
+
+	// test the partial line is logged on close
+	require.Nil(t, p.(io.Closer).Close())
+	require.Len(t, w.Buffer(), 3)
+	assert.Equal(t, "ERROR  [subproc] Giving up", w.Buffer()[2])
+	if got, ok := w.LevelAt(2); assert.True(t, ok, w.Buffer()) {
+		assert.Equal(t, ErrorLevel, got, w.Buffer())
+	}
+
+	// test an empty prefix
+	w.Reset()
+	_, err := NewPipeWriter(WarnLevel, "").Write([]byte("Connection slow\n"))
+	require.Nil(t, err)
+	assert.Equal(t, Buffer{"WARN   Connection slow"}, w.Buffer())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,71 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"os"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// Defines the names of the fields containing the process information, see SetProcessInfo.
+const (
+	pidFieldName  = "pid"
+	hostFieldName = "host"
+)
+
+// unknownHost defines the host name used when the host name cannot be determined.
+const unknownHost = "unknown"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _processFields defines the process information added to each log, captured when enabled by SetProcessInfo.
+var _processFields []Field
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetProcessInfo enables or disables tagging logs with the ID of the process and the host name, added as "pid" and
+// "host" field respectively. This helps to attribute the logs of multiple processes writing to a shared destination.
+// The information is captured once when enabled. The host name falls back to "unknown" if it cannot be determined.
+// Default and Pretty format render the information as key=value pairs, such as host=web-1 pid=4242.
+func SetProcessInfo(pid, host bool) {
+	var fields []Field
+	if pid {
+		fields = append(fields, Field{Key: pidFieldName, Value: os.Getpid()})
+	}
+	if host {
+		name, err := os.Hostname()
+		if err != nil || name == "" {
+			name = unknownHost
+		}
+		fields = append(fields, Field{Key: hostFieldName, Value: name})
+	}
+	_processFields = fields
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
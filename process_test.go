@@ -0,0 +1,61 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSetProcessInfo(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	// test the fields are added when enabled
+	SetProcessInfo(true, true)
+	defer SetProcessInfo(false, false)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 1)
+	var evt map[string]interface{}
+	require.Nil(t, json.Unmarshal([]byte(w.Buffer()[0]), &evt))
+	assert.Equal(t, float64(os.Getpid()), evt["pid"])
+	assert.Equal(t, host, evt["host"])
+
+	// test the fields are rendered as key=value pairs in Default format
+	w.Reset()
+	SetFormatting(Default, true)
+	SetProcessInfo(true, false)
+	Info("Listing snapshots")
+	assert.Equal(t, Buffer{fmt.Sprintf("Listing snapshots pid=%d", os.Getpid())}, w.Buffer())
+
+	// test the fields are omitted when disabled
+	w.Reset()
+	SetProcessInfo(false, false)
+	Info("Listing snapshots")
+	assert.Equal(t, Buffer{"Listing snapshots"}, w.Buffer())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,60 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _quietMu protects the quiet state.
+var _quietMu sync.Mutex
+
+// _quietRestore restores the global level before quiet was enabled, or is nil if quiet is disabled.
+var _quietRestore func()
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetQuiet enables or disables quiet mode, for example to implement a --quiet flag of a command-line tool. When
+// enabled, the global level is raised to WarnLevel, suppressing info, debug, and trace logs. A higher global level is
+// kept as-is. When disabled, the global level before enabling quiet mode is restored. Quiet mode takes precedence over
+// a lower level set before, such as DebugLevel for a --verbose flag, which applies again once quiet mode is disabled.
+// Quiet mode is implemented as a TemporaryLevel, as such calling SetGlobalLevel while quiet overrides the quiet level.
+func SetQuiet(quiet bool) {
+	_quietMu.Lock()
+	defer _quietMu.Unlock()
+
+	switch {
+	case quiet && _quietRestore == nil:
+		level := GlobalLevel()
+		if level < WarnLevel {
+			level = WarnLevel
+		}
+		_quietRestore = TemporaryLevel(level)
+	case !quiet && _quietRestore != nil:
+		_quietRestore()
+		_quietRestore = nil
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
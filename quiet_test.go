@@ -0,0 +1,58 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSetQuiet(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test info logs are suppressed while quiet
+	SetQuiet(true)
+	Info("Listing snapshots")
+	Warn("Snapshot missing")
+	assert.Equal(t, Buffer{"WARN   Snapshot missing"}, w.Buffer())
+
+	// test info logs are restored, enabling quiet twice has no effect
+	SetQuiet(true)
+	SetQuiet(false)
+	assert.Equal(t, InfoLevel, GlobalLevel())
+	Info("Listing snapshots")
+	assert.Equal(t, Buffer{"WARN   Snapshot missing", "Listing snapshots"}, w.Buffer())
+
+	// test quiet takes precedence over a verbose level, which is restored afterwards
+	SetGlobalLevel(DebugLevel)
+	SetQuiet(true)
+	assert.Equal(t, WarnLevel, GlobalLevel())
+	SetQuiet(false)
+	assert.Equal(t, DebugLevel, GlobalLevel())
+
+	// test a higher level is kept
+	SetGlobalLevel(ErrorLevel)
+	SetQuiet(true)
+	assert.Equal(t, ErrorLevel, GlobalLevel())
+	SetQuiet(false)
+	assert.Equal(t, ErrorLevel, GlobalLevel())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
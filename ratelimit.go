@@ -0,0 +1,136 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _rateMu protects the rate limits and drop counters.
+var _rateMu sync.RWMutex
+
+// _rateLimits defines the token bucket of each rate-limited level.
+var _rateLimits = map[Level]*tokenBucket{}
+
+// _dropped defines the number of messages dropped by the rate limit of each level.
+var _dropped = map[Level]*uint64{}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// tokenBucket implements a token bucket that holds up to rate tokens and is refilled at rate tokens per second.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// allow returns true if a message of the given level is within the rate limit of that level. Messages below the global
+// level are always allowed, as they are discarded by zerolog and should not consume tokens. Otherwise, the drop counter
+// of the level is incremented when the message exceeds the limit.
+func allow(level Level) bool {
+	if level < GlobalLevel() {
+		return true
+	}
+
+	_rateMu.RLock()
+	defer _rateMu.RUnlock()
+	b, ok := _rateLimits[level]
+	if !ok || b.take() {
+		return true
+	}
+	atomic.AddUint64(_dropped[level], 1)
+	return false
+}
+
+// take removes a token from the bucket, after refilling the bucket with the tokens accrued since the previous call. It
+// returns false if the bucket is empty.
+func (b *tokenBucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// DroppedCount returns the number of messages of the given level that have been dropped by the rate limit since the
+// limit was set.
+func DroppedCount(level Level) uint64 {
+	_rateMu.RLock()
+	defer _rateMu.RUnlock()
+	if n, ok := _dropped[level]; ok {
+		return atomic.LoadUint64(n)
+	}
+	return 0
+}
+
+// SetRateLimit limits the number of messages of the given level to perSecond messages per second, allowing bursts of
+// up to perSecond messages. Unlike sampling, messages exceeding the limit are dropped silently and counted, see
+// DroppedCount. A perSecond value of zero or less removes the limit. Fatal messages are never rate limited. Setting a
+// limit resets the drop counter of the level.
+func SetRateLimit(level Level, perSecond int) {
+	if level == FatalLevel {
+		return
+	}
+
+	_rateMu.Lock()
+	defer _rateMu.Unlock()
+	if perSecond <= 0 {
+		delete(_rateLimits, level)
+		return
+	}
+	rate := float64(perSecond)
+	_rateLimits[level] = &tokenBucket{rate: rate, tokens: rate, last: time.Now()}
+	_dropped[level] = new(uint64)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
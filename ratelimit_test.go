@@ -0,0 +1,71 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSetRateLimit(t *testing.T) {
+	const sent = 100
+	w := NewBufferedWriter(Default, true)
+	InitLoggerWithWriter(Default, true, w)
+	SetGlobalLevel(InfoLevel)
+	SetRateLimit(ErrorLevel, 10)
+
+	// test the flood is limited and all messages are accounted for
+	for i := 0; i < sent; i++ {
+		Error("Cannot connect")
+	}
+	emitted := len(w.Buffer())
+	assert.GreaterOrEqual(t, emitted, 10)
+	assert.Less(t, emitted, sent)
+	assert.Equal(t, uint64(sent), uint64(emitted)+DroppedCount(ErrorLevel))
+
+	// test other levels are not limited
+	w.Reset()
+	for i := 0; i < sent; i++ {
+		Warn("Connection slow")
+	}
+	assert.Len(t, w.Buffer(), sent)
+	assert.Equal(t, uint64(0), DroppedCount(WarnLevel))
+
+	// test fatal messages are exempt
+	SetRateLimit(FatalLevel, 1)
+	_suppressExit = true
+	w.Reset()
+	Fatal("Cannot recover")
+	Fatal("Cannot recover")
+	require.Len(t, w.Buffer(), 2)
+	_suppressExit = false
+
+	// test the limit can be removed
+	SetRateLimit(ErrorLevel, 0)
+	w.Reset()
+	for i := 0; i < sent; i++ {
+		Error("Cannot connect")
+	}
+	assert.Len(t, w.Buffer(), sent)
+
+	InitLogger(Default)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,51 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"io"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// rawWriter implements a log writer that forwards the JSON-formatted logs produced by zerolog as-is.
+type rawWriter struct {
+	out io.Writer
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewRawWriter creates a log writer that forwards the logs to out without any formatting transforms, for example to
+// pipe structured logs to another process. The logs are always written as raw JSON, regardless of the active format.
+func NewRawWriter(out io.Writer) Writer {
+	return &rawWriter{out: out}
+}
+
+// SetFormatting is a no-op for rawWriter, as logs are always written as raw JSON.
+func (w *rawWriter) SetFormatting(format Format, noColor bool) {}
+
+// Write implements the io.Writer interface for rawWriter.
+func (w *rawWriter) Write(p []byte) (n int, err error) {
+	return w.out.Write(p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,51 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestRawWriter(t *testing.T) {
+	// use a fixed timestamp to compare the output
+	zerolog.TimestampFunc = func() time.Time {
+		return time.Date(2020, 12, 17, 7, 12, 57, 0, time.UTC)
+	}
+	defer func() { zerolog.TimestampFunc = time.Now }()
+
+	// log a message using a raw writer in pretty format
+	var got bytes.Buffer
+	l := NewLogger(Pretty, false, NewRawWriter(&got))
+	l.With(Field{Key: "key", Value: "value"}).Info("raw message")
+
+	// log the same message using zerolog directly
+	var expected bytes.Buffer
+	z := zerolog.New(&expected).With().Timestamp().Logger()
+	z.Info().Str("key", "value").Msg("raw message")
+
+	assert.Equal(t, expected.String(), got.String())
+	assert.Equal(t, `{"level":"info","key":"value","time":"2020-12-17T07:12:57Z","message":"raw message"}`+"\n",
+		got.String())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
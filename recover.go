@@ -0,0 +1,102 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// logPanic logs the recovered value of a panic as error, including the stack trace of the panicking goroutine. If
+// stack traces are enabled and the value is an error carrying its own stack trace, the stack trace of the error is
+// logged instead, as both use the same field.
+func logPanic(r interface{}) {
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+	var fields []Field
+	if !_stackTrace || marshalStack(err) == nil {
+		fields = []Field{{Key: zerolog.ErrorStackFieldName, Value: panicStack()}}
+	}
+	_logger.log(ErrorLevel, fields, "Recovered from panic", err)
+}
+
+// panicStack returns the stack trace of the panicking goroutine, starting with the function that panicked. Frames of
+// the runtime and of this package's recovery functions are omitted. Each frame is formatted as "file:line function".
+func panicStack() []string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []string
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "runtime.") && !isRecoverFrame(f.Function) {
+			stack = append(stack, fmt.Sprintf("%s:%d %s", filepath.Base(f.File), f.Line, f.Function))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// isRecoverFrame returns true if the function belongs to the recovery functions of this package.
+func isRecoverFrame(function string) bool {
+	for _, name := range []string{"logPanic", "panicStack", "Recover", "RecoverAndRepanic"} {
+		if strings.HasSuffix(function, "/log."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// Recover recovers from a panic and logs the panic value as error, including a stack trace, instead of crashing the
+// program. Recover only works when it is deferred directly, such as defer log.Recover(), as Go's recover has no effect
+// when called by a function wrapping Recover. The execution continues after the function that deferred Recover
+// returns. Use Recover at the start of a goroutine, for example to keep a server running when a request handler panics.
+func Recover() {
+	if r := recover(); r != nil {
+		logPanic(r)
+	}
+}
+
+// RecoverAndRepanic logs the value of a panic as error, including a stack trace, similar to Recover. It then panics
+// again with the same value, for cases where crashing the program is still desired. Like Recover, it only works when
+// it is deferred directly, such as defer log.RecoverAndRepanic().
+func RecoverAndRepanic() {
+	if r := recover(); r != nil {
+		logPanic(r)
+		panic(r)
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,80 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+// panicking panics with the value, deferring the recovery function directly.
+func panicking(value interface{}, recoverFn func()) {
+	defer recoverFn()
+	panic(value)
+}
+
+func TestRecover(t *testing.T) {
+	w := newTestLogger(t, JSON)
+
+	// test the panic value and stack trace are logged
+	panicking("boom", Recover)
+	require.Len(t, w.Buffer(), 1)
+	assert.Regexp(t, `^{"level":"error","stack":\["recover_test.go:\d+ go.markdumay.org/log.panicking",`+
+		`.*"error":"boom",.*"message":"Recovered from panic"}$`, w.Buffer()[0])
+	assert.NotContains(t, w.Buffer()[0], "runtime.")
+	assert.NotContains(t, w.Buffer()[0], "log.Recover")
+
+	// test an error value is logged as-is
+	panicking(errors.New("failure"), Recover)
+	require.Len(t, w.Buffer(), 2)
+	assert.Contains(t, w.Buffer()[1], `"error":"failure"`)
+
+	// test the stack trace is rendered below the message in Default format
+	w.Reset()
+	SetFormatting(Default, true)
+	panicking("boom", Recover)
+	require.GreaterOrEqual(t, len(w.Buffer()), 2)
+	assert.Equal(t, "ERROR  Recovered from panic error=boom", w.Buffer()[0])
+	assert.Regexp(t, `^\trecover_test.go:\d+ go.markdumay.org/log.panicking$`, w.Buffer()[1])
+
+	// test the panic is raised again after logging
+	w.Reset()
+	assert.PanicsWithValue(t, "boom", func() { panicking("boom", RecoverAndRepanic) })
+	assert.Len(t, w.LinesAtLevel(ErrorLevel), 1)
+
+	// test the stack trace is logged once when the error carries its own stack trace
+	w.Reset()
+	SetFormatting(JSON, true)
+	SetStackTrace(true)
+	defer SetStackTrace(false)
+	panicking(stackError{msg: "failure"}, Recover)
+	require.Len(t, w.Buffer(), 1)
+	assert.Equal(t, 1, strings.Count(w.Buffer()[0], `"stack":`))
+
+	// test the panic stack trace is kept when the error has no stack trace
+	panicking("boom", Recover)
+	require.Len(t, w.Buffer(), 2)
+	assert.Equal(t, 1, strings.Count(w.Buffer()[1], `"stack":`))
+	assert.Contains(t, w.Buffer()[1], "log.panicking")
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
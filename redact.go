@@ -0,0 +1,181 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// redactMask defines the replacement of sensitive values.
+const redactMask = "***"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _jsonString matches a string literal in a JSON-formatted log, including escaped characters.
+var _jsonString = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// _redactMu protects the state of the built-in redaction patterns.
+var _redactMu sync.RWMutex
+
+// _redactPatterns defines the built-in redaction patterns in order of application.
+var _redactPatterns = []redactPattern{
+	{name: "email", re: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{name: "bearer", re: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)},
+	{name: "creditcard", re: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)},
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// redactPattern defines a built-in redaction pattern that can be toggled by name.
+type redactPattern struct {
+	name     string
+	re       *regexp.Regexp
+	disabled bool
+}
+
+// redactingWriter implements a log writer that masks sensitive values before they are written to the wrapped writer.
+type redactingWriter struct {
+	writer   Writer
+	patterns []*regexp.Regexp
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// redact masks all matches of the enabled built-in patterns and the custom patterns within the string literals of a
+// JSON-formatted log. Other values, such as numbers, are left untouched to keep the log valid.
+func (w *redactingWriter) redact(p []byte) []byte {
+	_redactMu.RLock()
+	patterns := make([]*regexp.Regexp, 0, len(_redactPatterns)+len(w.patterns))
+	for _, r := range _redactPatterns {
+		if !r.disabled {
+			patterns = append(patterns, r.re)
+		}
+	}
+	_redactMu.RUnlock()
+	patterns = append(patterns, w.patterns...)
+
+	return _jsonString.ReplaceAllFunc(p, func(s []byte) []byte {
+		for _, re := range patterns {
+			s = re.ReplaceAll(s, []byte(redactMask))
+		}
+		return s
+	})
+}
+
+// writeBypass writes the redacted log to the wrapped writer in Default format, if supported.
+func (w *redactingWriter) writeBypass(p []byte) (n int, err error) {
+	if _, err := bypass(w.writer, w.redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewRedactingWriter creates a log writer that replaces sensitive values with "***" before passing the log to w. It
+// masks matches of the enabled built-in patterns, being "email", "bearer" (bearer tokens), and "creditcard" (credit
+// card numbers), followed by matches of the custom patterns. All built-in patterns are enabled by default, use
+// SetRedaction to toggle them. The redaction applies to the text of the message, the error, and any string fields.
+// As the wrapped writer renders the redacted log, redaction works for all formats.
+func NewRedactingWriter(w Writer, patterns ...*regexp.Regexp) Writer {
+	return &redactingWriter{writer: w, patterns: patterns}
+}
+
+// SetRedaction enables or disables a built-in redaction pattern of all redacting writers by name, either "email",
+// "bearer", or "creditcard". It returns an error if the name is unknown.
+func SetRedaction(name string, enabled bool) error {
+	_redactMu.Lock()
+	defer _redactMu.Unlock()
+
+	for i := range _redactPatterns {
+		if _redactPatterns[i].name == name {
+			_redactPatterns[i].disabled = !enabled
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown redaction pattern: '%s'", name)
+}
+
+// Close closes the wrapped writer if it implements io.Closer.
+func (w *redactingWriter) Close() error {
+	if c, ok := w.writer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Flush flushes the wrapped writer, if it implements Flusher.
+func (w *redactingWriter) Flush() error {
+	return flush(w.writer)
+}
+
+// FlushContext flushes the wrapped writer until the context is done, see FlushWithTimeout.
+func (w *redactingWriter) FlushContext(ctx context.Context) error {
+	return flushWriter(ctx, w.writer)
+}
+
+// SetFormatting updates the log format and color coding of the wrapped writer.
+func (w *redactingWriter) SetFormatting(format Format, noColor bool) {
+	w.writer.SetFormatting(format, noColor)
+}
+
+// Write implements the io.Writer interface for redactingWriter. It expects a single JSON-formatted log message.
+func (w *redactingWriter) Write(p []byte) (n int, err error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for redactingWriter. It passes the level to the wrapped
+// writer if supported.
+func (w *redactingWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	if _, err := writeLevel(w.writer, level, w.redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,79 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestRedactingWriter(t *testing.T) {
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{format: JSON, expected: `"message":"Login by *** with ***, card ***, pin ***"`},
+		{format: Pretty, expected: `| WARN   | Login by *** with ***, card ***, pin *** user=***`},
+	}
+
+	for _, tc := range tests {
+		w := NewBufferedWriter(tc.format, true)
+		l := NewLogger(tc.format, true, NewRedactingWriter(w, regexp.MustCompile(`\b\d{4}\b`)))
+
+		// test built-in and custom patterns across formats
+		l.With(Field{Key: "user", Value: "jane@example.com"}).
+			Warn("Login by jane@example.com with Bearer abc.DEF-123=, card 4111 1111 1111 1111, pin 1234")
+		lines := w.Buffer()
+		require.Len(t, lines, 1)
+		assert.Contains(t, lines[0], tc.expected)
+		assert.NotContains(t, lines[0], "example.com")
+	}
+}
+
+func TestSetRedaction(t *testing.T) {
+	defer SetRedaction("email", true) //nolint:errcheck // known pattern
+
+	w := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, NewRedactingWriter(w))
+
+	// test a disabled built-in pattern is not applied
+	require.Nil(t, SetRedaction("email", false))
+	l.Info("Login by jane@example.com with bearer token")
+	assert.Contains(t, strings.Join(w.Buffer(), "\n"), `"message":"Login by jane@example.com with ***"`)
+
+	// test an unknown pattern
+	assert.NotNil(t, SetRedaction("phone", true))
+}
+
+func TestRedactingWriterLevel(t *testing.T) {
+	lw := &levelWriter{}
+	l := NewLogger(JSON, true, NewRedactingWriter(lw))
+
+	// test the level is passed to the wrapped writer
+	l.Error("Cannot notify admin@example.com")
+	_, err := l.writers[0].Write([]byte(`{"message":"raw"}`))
+	require.Nil(t, err)
+	assert.Equal(t, []zerolog.Level{zerolog.ErrorLevel, zerolog.NoLevel}, lw.levels)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,111 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// sequenceFieldName defines the name of the field containing the sequence number.
+const sequenceFieldName = "seq"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _sequence indicates whether logs are tagged with a sequence number.
+var _sequence bool
+
+// _sequenceSeq defines the most recently assigned sequence number.
+var _sequenceSeq uint64
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// logTags defines the sequence number and goroutine tag of a log, see SetSequence and SetGoroutineTag. A zero value
+// indicates the tag is disabled.
+type logTags struct {
+	seq uint64
+	gid uint64
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// nextSequence returns the next sequence number, it is safe for concurrent use.
+func nextSequence() uint64 {
+	return atomic.AddUint64(&_sequenceSeq, 1)
+}
+
+// newLogTags returns the tags of a log created by the calling goroutine, assigning the next sequence number if enabled.
+func newLogTags() logTags {
+	var t logTags
+	if _sequence {
+		t.seq = nextSequence()
+	}
+	if _goroutineTag {
+		t.gid = goroutineTag()
+	}
+	return t
+}
+
+// appendTo adds the tags that are set to the event.
+func (t logTags) appendTo(e *zerolog.Event) *zerolog.Event {
+	if t.seq != 0 {
+		e = e.Uint64(sequenceFieldName, t.seq)
+	}
+	if t.gid != 0 {
+		e = e.Uint64(goroutineFieldName, t.gid)
+	}
+	return e
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetSequence enables or disables tagging logs with a process-wide sequence number, added as "seq" field. The number
+// is incremented atomically for each log, and is shared by all loggers and writers. This helps to restore the order of
+// logs collected from multiple writers, and to detect dropped logs. The counter continues when the logger is
+// reconfigured or the sequence is disabled, but resets when the process restarts. Held messages are numbered when
+// logged, not when flushed.
+func SetSequence(enabled bool) {
+	_sequence = enabled
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
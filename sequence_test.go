@@ -0,0 +1,82 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSetSequence(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test each log is tagged with a strictly increasing sequence number, also after reconfiguring the logger
+	SetSequence(true)
+	defer SetSequence(false)
+	for i := 0; i < 3; i++ {
+		Info("Listing snapshots")
+	}
+	SetFormatting(JSON, true)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 4)
+	var prev float64
+	for _, line := range w.Buffer() {
+		var evt map[string]interface{}
+		require.Nil(t, json.Unmarshal([]byte(line), &evt))
+		seq, ok := evt["seq"].(float64)
+		require.True(t, ok)
+		assert.Greater(t, seq, prev)
+		prev = seq
+	}
+
+	// test the sequence number is omitted when disabled
+	w.Reset()
+	SetSequence(false)
+	Info("Listing snapshots")
+	require.Len(t, w.Buffer(), 1)
+	assert.NotContains(t, w.Buffer()[0], `"seq"`)
+}
+
+func TestSequenceHeld(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	SetSequence(true)
+	defer SetSequence(false)
+
+	// test held messages are numbered when logged, before logs written while they are held
+	Hold()
+	Info("held")
+	other := NewBufferedWriter(JSON, true)
+	NewLogger(JSON, true, other).Info("written")
+	Flush()
+
+	require.Len(t, w.Buffer(), 1)
+	require.Len(t, other.Buffer(), 1)
+	var held, written map[string]interface{}
+	require.Nil(t, json.Unmarshal([]byte(w.Buffer()[0]), &held))
+	require.Nil(t, json.Unmarshal([]byte(other.Buffer()[0]), &written))
+	assert.Less(t, held["seq"], written["seq"])
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,130 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _signalMu protects the active signal handler.
+var _signalMu sync.Mutex
+
+// _signalStop stops the active signal handler installed by FlushOnSignal, if any.
+var _signalStop func()
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// flushAll writes all buffered logs of the global logger and flushes any writers that queue logs internally, such as
+// AsyncWriter.
+func flushAll() {
+	Flush()
+	Sync() //nolint:errcheck // best effort during shutdown
+}
+
+// signalProcess sends the signal to the current process. This fails on platforms that do not support sending signals,
+// such as Windows.
+func signalProcess(sig os.Signal) error {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
+
+// raise sends the signal to the current process again using send, see signalProcess, so the default behavior of the
+// signal applies. If the signal cannot be sent, for example on Windows, the default behavior is restored using
+// signal.Reset and the program exits with code 128 plus the signal number, as reported by a shell, or 1 if the number
+// is unknown.
+func raise(send func(sig os.Signal) error, sig os.Signal) {
+	if err := send(sig); err == nil {
+		return
+	}
+
+	signal.Reset(sig)
+	code := 1
+	if s, ok := sig.(syscall.Signal); ok {
+		code = 128 + int(s)
+	}
+	if _suppressExit {
+		_exitCode = code
+		return
+	}
+	os.Exit(code)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// FlushOnSignal installs a handler that flushes the buffered logs and any writers that queue logs internally (such as
+// AsyncWriter) when the process receives one of the signals. By default, the handler listens for SIGINT and SIGTERM.
+// After flushing, the handler stops listening and raises the signal again, so the default behavior of the signal (such
+// as terminating the program) still applies. Other handlers registered by the program using signal.Notify receive the
+// signal as usual, but may observe it twice due to the re-raise. On platforms that cannot send signals to a process,
+// such as Windows, the handler resets the signal and exits the program with code 128 plus the signal number instead.
+// Only one handler is active at any time, calling
+// FlushOnSignal again replaces the previous handler. The returned function stops the handler.
+func FlushOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	_signalMu.Lock()
+	defer _signalMu.Unlock()
+	if _signalStop != nil {
+		_signalStop()
+	}
+
+	// listen for the signals until stopped
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		select {
+		case sig := <-ch:
+			flushAll()
+			signal.Stop(ch)
+			raise(signalProcess, sig)
+		case <-done:
+			signal.Stop(ch)
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() { close(done) })
+	}
+	_signalStop = stop
+
+	return stop
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
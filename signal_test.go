@@ -0,0 +1,98 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestFlushOnSignal(t *testing.T) {
+	// capture the signal in the test too, preventing the default behavior from terminating the test
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, syscall.SIGUSR1)
+	defer signal.Stop(received)
+
+	// hold several logs and write to an async writer
+	b := NewBufferedWriter(Default, true)
+	w := NewAsyncWriter(b, 10)
+	InitLoggerWithWriter(Default, true, w)
+	SetGlobalLevel(InfoLevel)
+	Hold()
+	Info("first message")
+	Info("second message")
+
+	// test duplicate calls replace the active handler
+	stop := FlushOnSignal(syscall.SIGUSR1)
+	stop = FlushOnSignal(syscall.SIGUSR1)
+	defer stop()
+
+	// test the logs are flushed when the signal is received
+	p, err := os.FindProcess(os.Getpid())
+	require.Nil(t, err)
+	require.Nil(t, p.Signal(syscall.SIGUSR1))
+	for i := 0; i < 2; i++ {
+		// wait for both the original and the re-raised signal
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			require.Fail(t, "signal not received")
+		}
+	}
+	require.Nil(t, w.Close())
+	got := b.Buffer()
+	require.Len(t, got, 2)
+	assert.Contains(t, got[0], "first message")
+	assert.Contains(t, got[1], "second message")
+
+	// restore the logger settings
+	InitLogger(Default)
+}
+
+// customSignal defines a signal without a number, used to test the exit code of a failed re-raise.
+type customSignal struct{}
+
+func (customSignal) String() string { return "custom" }
+func (customSignal) Signal()        {}
+
+func TestRaiseFallback(t *testing.T) {
+	_suppressExit = true
+	defer func() { _suppressExit = false }()
+
+	// test the program exits with 128 plus the signal number if the signal cannot be sent, as on Windows
+	send := func(sig os.Signal) error { return errors.New("not supported by windows") }
+	_exitCode = 0
+	raise(send, syscall.SIGUSR2)
+	assert.Equal(t, 128+int(syscall.SIGUSR2), _exitCode)
+
+	// test the program exits with code 1 if the signal number is unknown
+	raise(send, customSignal{})
+	assert.Equal(t, 1, _exitCode)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,157 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"context"
+	"log/slog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// slogHandler implements the slog.Handler interface, routing records of the standard library's structured logger to
+// the global logger. Attributes are converted to fields, using dotted key names for grouped attributes.
+type slogHandler struct {
+	fields []Field
+	prefix string
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// appendAttr converts a slog attribute into one or more fields, prefixing the key with the names of any groups.
+// Attributes with an empty key are ignored, unless they define a group, in which case the attributes of the group are
+// inlined.
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range v.Group() {
+			fields = appendAttr(fields, prefix, g)
+		}
+		return fields
+	}
+
+	if a.Key == "" {
+		return fields
+	}
+
+	key := prefix + a.Key
+	switch v.Kind() {
+	case slog.KindBool:
+		return append(fields, Field{Key: key, Value: v.Bool()})
+	case slog.KindDuration:
+		return append(fields, Field{Key: key, Value: v.Duration()})
+	case slog.KindFloat64:
+		return append(fields, Field{Key: key, Value: v.Float64()})
+	case slog.KindInt64:
+		return append(fields, Field{Key: key, Value: v.Int64()})
+	case slog.KindString:
+		return append(fields, Field{Key: key, Value: v.String()})
+	case slog.KindTime:
+		return append(fields, Field{Key: key, Value: v.Time()})
+	case slog.KindUint64:
+		return append(fields, Field{Key: key, Value: v.Uint64()})
+	default:
+		return append(fields, Field{Key: key, Value: v.Any()})
+	}
+}
+
+// fromSlogLevel converts a slog level into a Level. Levels below slog.LevelDebug are converted to TraceLevel.
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewSlogHandler creates a slog.Handler that routes the records of the standard library's structured logger to the
+// global logger. The records are rendered using the active format, level, and writers, just like native logs. The slog
+// levels are mapped to the nearest Level, and the attributes of a record are added as fields. Fields added within a
+// group, for example by WithGroup("http"), are dotted into key names such as "http.method". The time of the record is
+// ignored in favor of the timestamp added by the logger itself.
+//
+//	slog.SetDefault(slog.New(log.NewSlogHandler()))
+func NewSlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+// Enabled implements the slog.Handler interface. It reports whether the level is enabled by both the global level and
+// the level of the global logger.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	l := fromSlogLevel(level)
+	return l >= GlobalLevel() && l >= _logger.level
+}
+
+// Handle implements the slog.Handler interface. It logs the record using the global logger.
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]Field, 0, len(h.fields)+r.NumAttrs())
+	fields = append(fields, h.fields...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, a)
+		return true
+	})
+
+	_logger.log(fromSlogLevel(r.Level), fields, r.Message, nil)
+	return nil
+}
+
+// WithAttrs implements the slog.Handler interface. It returns a new handler that adds the attributes to each record.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]Field, 0, len(h.fields)+len(attrs))
+	fields = append(fields, h.fields...)
+	for _, a := range attrs {
+		fields = appendAttr(fields, h.prefix, a)
+	}
+	return &slogHandler{fields: fields, prefix: h.prefix}
+}
+
+// WithGroup implements the slog.Handler interface. It returns a new handler that prefixes the keys of subsequent
+// attributes with the group name, separated by a dot.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,68 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSlogHandler(t *testing.T) {
+	w := NewBufferedWriter(JSON, true)
+	InitLoggerWithWriter(JSON, true, w)
+	SetGlobalLevel(InfoLevel)
+
+	// log records using the standard library's structured logger
+	logger := slog.New(NewSlogHandler())
+	logger.Debug("debug message")
+	logger.Info("info message", "user", "admin", "attempt", 2)
+	logger.With("service", "api").WithGroup("http").Warn("warn message", "method", "GET", "status", 404)
+	logger.Error("error message", slog.Group("db", slog.String("table", "users")))
+
+	// test the records are rendered as JSON
+	got := w.Buffer()
+	require.Len(t, got, 3)
+	m, e := UnmarshalLog([]byte(got[0]))
+	require.Nil(t, e)
+	assert.Equal(t, InfoLevel, m.Level)
+	assert.Equal(t, "info message", m.Message)
+	assert.Contains(t, got[0], `"user":"admin","attempt":2`)
+	assert.Contains(t, got[1], `"level":"warn"`)
+	assert.Contains(t, got[1], `"service":"api","http.method":"GET","http.status":404`)
+	assert.Contains(t, got[2], `"level":"error"`)
+	assert.Contains(t, got[2], `"db.table":"users"`)
+
+	// test the records respect the active format
+	w.Reset()
+	SetFormatting(Pretty, true)
+	logger.Info("info message", "user", "admin")
+	got = w.Buffer()
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "| INFO   | info message user=admin")
+
+	// restore the logger settings
+	InitLogger(Default)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
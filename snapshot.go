@@ -0,0 +1,60 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// LoggerSnapshot captures the state of the global logger, see Snapshot.
+type LoggerSnapshot struct {
+	writers     []Writer
+	format      Format
+	noColor     bool
+	level       Level
+	globalLevel Level
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// Snapshot captures the writers, format, and color coding of the global logger, as well as the global level. Call
+// Restore on the snapshot to revert any changes made afterwards, for example in the teardown of a test:
+//
+//	snapshot := log.Snapshot()
+//	defer snapshot.Restore()
+//
+// The snapshot copies the list of writers, but not the writers themselves. Held messages are not captured.
+func Snapshot() *LoggerSnapshot {
+	writers := make([]Writer, len(_logger.writers))
+	copy(writers, _logger.writers)
+
+	return &LoggerSnapshot{
+		writers:     writers,
+		format:      _logger.format,
+		noColor:     _logger.noColor,
+		level:       _logger.level,
+		globalLevel: GlobalLevel(),
+	}
+}
+
+// Restore reverts the global logger to the state captured by Snapshot. The captured writers are reinstated with the
+// captured format and color coding. Held messages of the current logger are preserved.
+func (s *LoggerSnapshot) Restore() {
+	writers := make([]Writer, len(s.writers))
+	copy(writers, s.writers)
+
+	initLogger(s.format, s.noColor, writers...)
+	_logger.level = s.level
+	SetGlobalLevel(s.globalLevel)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
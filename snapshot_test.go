@@ -0,0 +1,55 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSnapshot(t *testing.T) {
+	var out bytes.Buffer
+	InitLoggerWithWriter(Pretty, false, NewConsoleWriter(Pretty, false, &out))
+	SetGlobalLevel(WarnLevel)
+	writers := _logger.writers
+
+	// test the original state returns after mutating the settings
+	snapshot := Snapshot()
+	SetFormatting(JSON, true)
+	SetGlobalLevel(DebugLevel)
+	AppendWriter(NewBufferedWriter(JSON, true))
+	SetWriters(NewBufferedWriter(Logfmt, true))
+	snapshot.Restore()
+
+	assert.Equal(t, Pretty, CurrentFormat())
+	assert.False(t, NoColor())
+	assert.Equal(t, WarnLevel, GlobalLevel())
+	assert.Equal(t, writers, _logger.writers)
+
+	// test the restored writers use the captured format
+	out.Reset()
+	Warn("Snapshot restored")
+	assert.Regexp(t, `\| .*WARN.* \| Snapshot restored`, out.String())
+
+	InitLogger(Default)
+	SetGlobalLevel(InfoLevel)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,217 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// fileRoute defines a log file of a splitFileWriter, together with the minimum level of the logs written to it.
+type fileRoute struct {
+	name   string
+	min    Level
+	file   *os.File
+	writer io.Writer // renders logs to file using the active format
+}
+
+// splitFileWriter implements a log writer that routes logs to one or more files, depending on their level. Each file
+// receives all logs that meet its minimum level. Logs are rendered using the active format without color coding.
+type splitFileWriter struct {
+	dir    string
+	format Format
+	routes []*fileRoute
+	mu     sync.Mutex
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// close closes all files of the splitFileWriter, returning an error describing all failures.
+func (w *splitFileWriter) close() error {
+	var errs multiError
+	for _, r := range w.routes {
+		if err := r.file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// open opens all files of the splitFileWriter in append mode, creating them if needed. Each file gets its own writer
+// for the active format, as such a CSV header is written once each time a file is opened. It closes any files opened
+// so far on error.
+func (w *splitFileWriter) open() error {
+	for i, r := range w.routes {
+		f, err := os.OpenFile(filepath.Join(w.dir, r.name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			for _, prev := range w.routes[:i] {
+				prev.file.Close() //nolint:errcheck // the error of opening the file takes precedence
+			}
+			return fmt.Errorf("Cannot open log file '%s': %s", r.name, err)
+		}
+		r.file = f
+		r.writer = newWriter(w.format, true, f)
+	}
+	return nil
+}
+
+// writeBypass writes the log in Default format to each file that accepts Info logs.
+func (w *splitFileWriter) writeBypass(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, r := range w.routes {
+		if InfoLevel < r.min {
+			continue
+		}
+		if _, err := newWriter(Default, true, r.file).Write(p); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewSplitFileWriter creates a log writer that writes logs to multiple files in dir, depending on their level. The
+// routes map the minimum level of each file to its name. A log is written to each file whose minimum level it meets,
+// for example an Error log is written to both the files "error.log" and "app.log" given the routes
+// {ErrorLevel: "error.log", TraceLevel: "app.log"}. Levels mapped to the same file name use the lowest level. The
+// directory is created if needed, and files are opened in append mode. Logs are rendered using format without color
+// coding. Note that the Logger updates the format of its writers, use AppendWriterWithFormat to keep the format
+// fixed. The writer implements io.Closer to close the files, and a Reopen method to reopen the files after rotation.
+func NewSplitFileWriter(dir string, format Format, routes map[Level]string) (Writer, error) {
+	if len(routes) == 0 {
+		return nil, errors.New("Cannot create split file writer, no routes defined")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("Cannot create log directory '%s': %s", dir, err)
+	}
+
+	// combine the routes by file name, keeping the lowest level
+	files := make(map[string]Level)
+	for level, name := range routes {
+		if min, ok := files[name]; !ok || level < min {
+			files[name] = level
+		}
+	}
+	w := &splitFileWriter{dir: dir, format: format}
+	for name, level := range files {
+		w.routes = append(w.routes, &fileRoute{name: name, min: level})
+	}
+	sort.Slice(w.routes, func(i, j int) bool { return w.routes[i].name < w.routes[j].name })
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close closes all files of the splitFileWriter.
+func (w *splitFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.close()
+}
+
+// Reopen closes and reopens all files of the splitFileWriter. Use Reopen after the files have been moved by an
+// external log rotation tool, to ensure new logs are written to new files. The files are reopened even if closing them
+// fails, in which case the errors of both steps are combined.
+func (w *splitFileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs multiError
+	if err := w.close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := w.open(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// SetFormatting updates the log format of an existing splitFileWriter. Color coding is always disabled.
+func (w *splitFileWriter) SetFormatting(format Format, noColor bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if format == w.format {
+		return
+	}
+	w.format = format
+	for _, r := range w.routes {
+		r.writer = newWriter(format, true, r.file)
+	}
+}
+
+// Write implements the io.Writer interface for splitFileWriter. Logs without a known level are considered to be Info
+// logs.
+func (w *splitFileWriter) Write(p []byte) (n int, err error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for splitFileWriter. It writes the log to each file whose
+// minimum level is met.
+func (w *splitFileWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	l := Level(level)
+	if level == zerolog.NoLevel {
+		l = InfoLevel
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, r := range w.routes {
+		if l < r.min {
+			continue
+		}
+		if _, err := r.writer.Write(p); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,108 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSplitFileWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewSplitFileWriter(dir, JSON, map[Level]string{ErrorLevel: "error.log", TraceLevel: "app.log"})
+	require.Nil(t, err)
+	InitLoggerWithWriter(Default, true, w)
+	SetGlobalLevel(InfoLevel)
+
+	// test the logs are routed by level, using the active format
+	Info("Listing snapshots")
+	Error("Cannot connect")
+	read := func(name string) []string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		require.Nil(t, err)
+		return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	}
+	assert.Equal(t, []string{"Listing snapshots", "ERROR  Cannot connect"}, read("app.log"))
+	assert.Equal(t, []string{"ERROR  Cannot connect"}, read("error.log"))
+
+	// test the files are reopened after rotation
+	require.Nil(t, os.Rename(filepath.Join(dir, "error.log"), filepath.Join(dir, "error.log.1")))
+	require.Nil(t, w.(interface{ Reopen() error }).Reopen())
+	SetFormatting(JSON, true)
+	Error("Cannot reconnect")
+	got := read("error.log")
+	require.Len(t, got, 1)
+	assert.Regexp(t, `^{"level":"error",.*"message":"Cannot reconnect"}$`, got[0])
+	assert.Len(t, read("app.log"), 3)
+	assert.Len(t, read("error.log.1"), 1)
+
+	require.Nil(t, w.(io.Closer).Close())
+	InitLogger(Default)
+
+	// test invalid routes
+	_, err = NewSplitFileWriter(dir, JSON, nil)
+	assert.EqualError(t, err, "Cannot create split file writer, no routes defined")
+}
+
+func TestSplitFileWriterCSV(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewSplitFileWriter(dir, CSV, map[Level]string{TraceLevel: "app.csv"})
+	require.Nil(t, err)
+	defer w.(io.Closer).Close()
+	InitLoggerWithWriter(CSV, true, w)
+	defer InitLogger(Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test the header is written once
+	Info("Listing snapshots")
+	Warn("Snapshot missing")
+	b, err := os.ReadFile(filepath.Join(dir, "app.csv"))
+	require.Nil(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "time,level,message,error", lines[0])
+	assert.Contains(t, lines[2], "Snapshot missing")
+}
+
+func TestSplitFileWriterReopenError(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewSplitFileWriter(dir, JSON, map[Level]string{TraceLevel: "app.log"})
+	require.Nil(t, err)
+	defer w.(io.Closer).Close()
+	InitLoggerWithWriter(JSON, true, w)
+	defer InitLogger(Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test the files are reopened even if closing them fails
+	require.Nil(t, w.(io.Closer).Close())
+	err = w.(interface{ Reopen() error }).Reopen()
+	assert.Contains(t, fmt.Sprint(err), "file already closed")
+	Info("Listing snapshots")
+	b, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.Nil(t, err)
+	assert.Contains(t, string(b), "Listing snapshots")
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,109 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _stackTrace indicates whether stack traces of errors are logged.
+var _stackTrace bool
+
+// _prevStackMarshaler defines zerolog's error stack marshaler that was active before stack traces were enabled, see
+// SetStackTrace.
+var _prevStackMarshaler func(err error) interface{}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// StackTracer defines the interface for errors that carry a stack trace. The stack trace is represented as a list of
+// frames, starting with the most recent call.
+type StackTracer interface {
+	error
+	StackTrace() []string
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// formatStack renders the stack trace of an event as an indented block, one frame per line. It implements the
+// FormatExtra function of zerolog.ConsoleWriter.
+func formatStack(evt map[string]interface{}, buf *bytes.Buffer) error {
+	frames, ok := evt[zerolog.ErrorStackFieldName].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, f := range frames {
+		fmt.Fprintf(buf, "\n\t%v", f)
+	}
+	return nil
+}
+
+// marshalStack retrieves the stack trace of the first error in the chain of err that implements StackTracer. It
+// returns nil if no stack trace is available.
+func marshalStack(err error) interface{} {
+	var st StackTracer
+	if errors.As(err, &st) {
+		if frames := st.StackTrace(); len(frames) > 0 {
+			return frames
+		}
+	}
+	return nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetStackTrace enables or disables logging the stack trace of errors. The stack trace is added as a "stack" field in
+// JSON format, and as an indented block below the message in Default and Pretty format. Errors need to implement
+// StackTracer, either directly or as part of their chain of wrapped errors. The stack trace of buffered logs is
+// retained until the logs are flushed. Note that enabling stack traces sets zerolog's global error stack marshaler,
+// while disabling them restores the marshaler that was active before.
+func SetStackTrace(enabled bool) {
+	switch {
+	case enabled && !_stackTrace:
+		_prevStackMarshaler = zerolog.ErrorStackMarshaler
+		zerolog.ErrorStackMarshaler = marshalStack
+	case !enabled && _stackTrace:
+		zerolog.ErrorStackMarshaler = _prevStackMarshaler
+		_prevStackMarshaler = nil
+	}
+	_stackTrace = enabled
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
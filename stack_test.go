@@ -0,0 +1,96 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+// stackError defines a sentinel error that carries a stack trace.
+type stackError struct {
+	msg string
+}
+
+func (e stackError) Error() string {
+	return e.msg
+}
+
+func (e stackError) StackTrace() []string {
+	return []string{"main.go:12 main.run", "main.go:5 main.main"}
+}
+
+func TestSetStackTrace(t *testing.T) {
+	SetStackTrace(true)
+	SetGlobalLevel(InfoLevel)
+	err := fmt.Errorf("wrapped: %w", stackError{msg: "failure"})
+
+	// test the stack is added as field in JSON format, including held messages
+	w := NewBufferedWriter(JSON, true)
+	l := NewLogger(JSON, true, w)
+	l.Hold()
+	l.ErrorE(err, "json message")
+	l.Flush()
+	l.ErrorE(errors.New("plain"), "no stack")
+	got := w.Buffer()
+	require.Len(t, got, 2)
+	assert.Contains(t, got[0], `"stack":["main.go:12 main.run","main.go:5 main.main"],"error":"wrapped: failure"`)
+	assert.NotContains(t, got[1], `"stack"`)
+
+	// test the stack is rendered as indented block in Pretty format
+	w = NewBufferedWriter(Pretty, true)
+	l = NewLogger(Pretty, true, w)
+	l.ErrorE(err, "pretty message")
+	got = w.Buffer()
+	require.Len(t, got, 3)
+	assert.Contains(t, got[0], "| ERROR  | pretty message error=\"wrapped: failure\"")
+	assert.Equal(t, "\tmain.go:12 main.run", got[1])
+	assert.Equal(t, "\tmain.go:5 main.main", got[2])
+
+	// test the stack is omitted when disabled
+	SetStackTrace(false)
+	w = NewBufferedWriter(JSON, true)
+	l = NewLogger(JSON, true, w)
+	l.ErrorE(err, "json message")
+	require.Len(t, w.Buffer(), 1)
+	assert.NotContains(t, w.Buffer()[0], `"stack"`)
+}
+
+func TestSetStackTraceRestore(t *testing.T) {
+	prev := zerolog.ErrorStackMarshaler
+	defer func() { zerolog.ErrorStackMarshaler = prev }()
+	custom := func(err error) interface{} { return "custom" }
+	zerolog.ErrorStackMarshaler = custom
+
+	// test the marshaler of the application is restored when stack traces are disabled, also after repeated calls
+	SetStackTrace(true)
+	SetStackTrace(true)
+	assert.Equal(t, []string{"main.go:12 main.run", "main.go:5 main.main"},
+		zerolog.ErrorStackMarshaler(stackError{msg: "failure"}))
+	SetStackTrace(false)
+	SetStackTrace(false)
+	require.NotNil(t, zerolog.ErrorStackMarshaler)
+	assert.Equal(t, "custom", zerolog.ErrorStackMarshaler(errors.New("failure")))
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
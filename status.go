@@ -0,0 +1,66 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _statusMapping defines the function mapping HTTP status codes to levels, see SetStatusMapping.
+var _statusMapping = defaultStatusLevel
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// defaultStatusLevel maps server errors (5xx) to ErrorLevel, client errors (4xx) to WarnLevel, and all other HTTP
+// status codes to InfoLevel.
+func defaultStatusLevel(code int) Level {
+	switch {
+	case code >= 500:
+		return ErrorLevel
+	case code >= 400:
+		return WarnLevel
+	default:
+		return InfoLevel
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetStatusMapping overrides the function that maps HTTP status codes to levels, as used by Status and StatusE. A nil
+// function restores the default mapping, which logs server errors (5xx) as errors, client errors (4xx) as warnings,
+// and all other status codes as info messages.
+func SetStatusMapping(f func(code int) Level) {
+	if f == nil {
+		f = defaultStatusLevel
+	}
+	_statusMapping = f
+}
+
+// Status logs a message at the level derived from an HTTP status code, for example in a web middleware. By default,
+// server errors (5xx) are logged as errors, client errors (4xx) as warnings, and all other status codes as info
+// messages. Use SetStatusMapping to customize the mapping.
+func Status(code int, msg string) {
+	_logger.log(_statusMapping(code), nil, msg, nil)
+}
+
+// StatusE logs an error at the level derived from an HTTP status code, see Status.
+func StatusE(code int, e error, msg string) {
+	_logger.log(_statusMapping(code), nil, msg, e)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
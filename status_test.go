@@ -0,0 +1,60 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestStatus(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+
+	// test the default mapping of each range
+	Status(101, "Switching protocols")
+	Status(200, "Request handled")
+	Status(304, "Not modified")
+	Status(404, "Page not found")
+	StatusE(503, errors.New("timeout"), "Service unavailable")
+	assert.Equal(t, Buffer{"Switching protocols", "Request handled", "Not modified", "WARN   Page not found",
+		"ERROR  Service unavailable error=timeout"}, w.Buffer())
+
+	// test a custom mapping
+	w.Reset()
+	SetStatusMapping(func(code int) Level {
+		if code == 404 {
+			return DebugLevel
+		}
+		return ErrorLevel
+	})
+	Status(404, "Page not found")
+	Status(200, "Request handled")
+	assert.Equal(t, Buffer{"DEBUG  Page not found", "ERROR  Request handled"}, w.Buffer())
+
+	// test the default mapping is restored
+	w.Reset()
+	SetStatusMapping(nil)
+	Status(404, "Page not found")
+	assert.Equal(t, Buffer{"WARN   Page not found"}, w.Buffer())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,94 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	stdlog "log"
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// stdlogWriter implements a log writer that forwards logs to a logger of the Go standard library. It renders each log
+// using the active format and color coding.
+type stdlogWriter struct {
+	logger  *stdlog.Logger
+	format  Format
+	noColor bool
+	mu      sync.Mutex
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// output renders a JSON-formatted log using the given writer settings and passes it to the standard library logger,
+// omitting the trailing newline as the standard library logger adds its own.
+func (w *stdlogWriter) output(p []byte, format Format, noColor bool) (n int, err error) {
+	var buf bytes.Buffer
+	if _, err := newWriter(format, noColor, &buf).Write(p); err != nil {
+		return 0, err
+	}
+	if err := w.logger.Output(2, string(bytes.TrimRight(buf.Bytes(), "\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeBypass writes the log in Default format without color coding.
+func (w *stdlogWriter) writeBypass(p []byte) (n int, err error) {
+	return w.output(p, Default, true)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewStdlogWriter creates a log writer that forwards logs to a logger of the Go standard library, such as the logger
+// returned by log.Default(). Each log is rendered using the active format and written as a single entry by calling
+// l.Output. The destination, prefix, and flags of the standard library logger still apply. As such, consider
+// disabling the flags of l to avoid duplicate timestamps.
+func NewStdlogWriter(l *stdlog.Logger) Writer {
+	return &stdlogWriter{logger: l}
+}
+
+// SetFormatting updates the log format and color coding of an existing stdlogWriter.
+func (w *stdlogWriter) SetFormatting(format Format, noColor bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.format = format
+	w.noColor = noColor
+}
+
+// Write implements the io.Writer interface for stdlogWriter. It expects a single JSON-formatted log message.
+func (w *stdlogWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	format, noColor := w.format, w.noColor
+	w.mu.Unlock()
+
+	return w.output(p, format, noColor)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,45 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	stdlog "log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestStdlogWriter(t *testing.T) {
+	var out bytes.Buffer
+	std := stdlog.New(&out, "legacy: ", 0)
+	l := NewLogger(Default, true, NewStdlogWriter(std))
+
+	// test logs are rendered in the active format without double newlines
+	l.Info("Listing snapshots")
+	l.Warn("Snapshot missing")
+	assert.Equal(t, "legacy: Listing snapshots\nlegacy: WARN   Snapshot missing\n", out.String())
+
+	// test the format is updated
+	out.Reset()
+	l = NewLogger(Logfmt, true, NewStdlogWriter(std))
+	l.Info("Listing snapshots")
+	assert.Regexp(t, `^legacy: level=info msg="Listing snapshots" time=\S+\n$`, out.String())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
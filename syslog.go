@@ -0,0 +1,154 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"bytes"
+	"io"
+	"log/syslog"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// syslogWriter implements a log writer that forwards logs to a syslog daemon. It renders each log using the active
+// format without color coding, and maps the level of the log to the corresponding syslog severity.
+type syslogWriter struct {
+	writer   *syslog.Writer
+	format   Format
+	renderer io.Writer    // renders logs to buf using the active format
+	bypasser io.Writer    // renders logs to buf in Default format
+	buf      bytes.Buffer // collects the rendered log
+	mu       sync.Mutex   // protects the format, the renderers, and buf
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// render converts a JSON-formatted log into the active format of the syslogWriter, or into Default format when
+// bypassing the active format. The trailing newline is omitted.
+func (w *syslogWriter) render(p []byte, bypass bool) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r := w.renderer
+	if bypass {
+		r = w.bypasser
+	}
+	w.buf.Reset()
+	if _, err := r.Write(p); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimRight(w.buf.Bytes(), "\n")), nil
+}
+
+// writeBypass writes the log in Default format with the LOG_INFO severity.
+func (w *syslogWriter) writeBypass(p []byte) (n int, err error) {
+	m, err := w.render(p, true)
+	if err != nil {
+		return 0, err
+	}
+	if err := w.writer.Info(m); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewSyslogWriter creates a log writer that connects to a syslog daemon at the specified network address, using the
+// user facility. An empty network and address connects to the local syslog daemon. The tag is used as program name of
+// the messages. Each level is mapped to a syslog severity, for example DebugLevel to LOG_DEBUG and ErrorLevel to
+// LOG_ERR. NewSyslogWriter returns an error on platforms that do not support syslog.
+func NewSyslogWriter(network, addr, tag string) (Writer, error) {
+	s, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &syslogWriter{writer: s}
+	w.renderer = newWriter(w.format, true, &w.buf)
+	w.bypasser = newWriter(Default, true, &w.buf)
+	return w, nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (w *syslogWriter) Close() error {
+	return w.writer.Close()
+}
+
+// SetFormatting updates the log format of an existing syslogWriter. Color coding is always disabled.
+func (w *syslogWriter) SetFormatting(format Format, noColor bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if format != w.format {
+		w.format = format
+		w.renderer = newWriter(format, true, &w.buf)
+	}
+}
+
+// Write implements the io.Writer interface for syslogWriter. Logs without a known level are written with the
+// LOG_INFO severity.
+func (w *syslogWriter) Write(p []byte) (n int, err error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for syslogWriter. It maps the level to the corresponding
+// syslog severity.
+func (w *syslogWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	m, err := w.render(p, false)
+	if err != nil {
+		return 0, err
+	}
+
+	switch Level(level) {
+	case TraceLevel, DebugLevel:
+		err = w.writer.Debug(m)
+	case WarnLevel:
+		err = w.writer.Warning(m)
+	case ErrorLevel:
+		err = w.writer.Err(m)
+	case FatalLevel:
+		err = w.writer.Crit(m)
+	case PanicLevel:
+		err = w.writer.Emerg(m)
+	default:
+		err = w.writer.Info(m)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
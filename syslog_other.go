@@ -0,0 +1,32 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+//go:build windows || plan9
+// +build windows plan9
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// NewSyslogWriter is not supported on this platform and always returns an error.
+func NewSyslogWriter(network, addr, tag string) (Writer, error) {
+	return nil, errors.New("Syslog is not supported on this platform")
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
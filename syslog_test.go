@@ -0,0 +1,75 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestSyslogWriter(t *testing.T) {
+	// listen on a local unix datagram socket
+	addr := filepath.Join(t.TempDir(), "syslog.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	require.Nil(t, err)
+	defer conn.Close()
+
+	// log messages at different levels
+	w, err := NewSyslogWriter("unixgram", addr, "test")
+	require.Nil(t, err)
+	l := NewLogger(Default, false, w)
+	l.SetLevel(DebugLevel)
+	SetGlobalLevel(DebugLevel)
+	l.Error("error message")
+	l.Debug("debug message")
+	l.Info("info message")
+
+	// test the messages arrive with the expected priority (user facility is 8)
+	type test struct {
+		priority string
+		message  string
+	}
+	var tests = []test{
+		{priority: "<11>", message: "ERROR  error message"},
+		{priority: "<15>", message: "DEBUG  debug message"},
+		{priority: "<14>", message: "info message"},
+	}
+	buf := make([]byte, 1024)
+	for _, test := range tests {
+		n, err := conn.Read(buf)
+		require.Nil(t, err)
+		got := string(buf[:n])
+		assert.Regexp(t, "^"+test.priority, got)
+		assert.Contains(t, got, "test[")
+		assert.Contains(t, got, test.message)
+		assert.NotContains(t, got, "\x1b[")
+	}
+
+	// restore the logger settings
+	require.Nil(t, w.(interface{ Close() error }).Close())
+	SetGlobalLevel(InfoLevel)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
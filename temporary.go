@@ -0,0 +1,91 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"sync"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _overrideMu protects the temporary levels.
+var _overrideMu sync.Mutex
+
+// _overrides defines the active temporary levels in order of activation.
+var _overrides []*levelOverride
+
+// _overrideBase defines the global level before the first active temporary level.
+var _overrideBase Level
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// levelOverride defines a temporary global level, see TemporaryLevel.
+type levelOverride struct {
+	level Level
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// TemporaryLevel sets the global level until the returned function is called, for example to enable debug logs
+// during a specific operation. The returned function restores the prior level and can be deferred. Calling it more
+// than once has no effect. Note that the global level is process-wide, affecting all goroutines and loggers. Scopes may
+// overlap and may be restored in any order, the most recently activated scope that is still active determines the
+// global level. Once all scopes are restored, the global level reverts to the level before the first scope.
+func TemporaryLevel(level Level) (restore func()) {
+	o := &levelOverride{level: level}
+
+	_overrideMu.Lock()
+	if len(_overrides) == 0 {
+		_overrideBase = GlobalLevel()
+	}
+	_overrides = append(_overrides, o)
+	SetGlobalLevel(level)
+	_overrideMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			_overrideMu.Lock()
+			defer _overrideMu.Unlock()
+
+			for i, curr := range _overrides {
+				if curr == o {
+					_overrides = append(_overrides[:i], _overrides[i+1:]...)
+					break
+				}
+			}
+			if len(_overrides) == 0 {
+				SetGlobalLevel(_overrideBase)
+			} else {
+				SetGlobalLevel(_overrides[len(_overrides)-1].level)
+			}
+		})
+	}
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
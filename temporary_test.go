@@ -0,0 +1,55 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestTemporaryLevel(t *testing.T) {
+	w := newTestLogger(t, Default)
+	SetGlobalLevel(InfoLevel)
+
+	// test debug logs appear within the scope only
+	restore := TemporaryLevel(DebugLevel)
+	Debug("within scope")
+	restore()
+	Debug("after restore")
+	assert.Equal(t, Buffer{"DEBUG  within scope"}, w.Buffer())
+	assert.Equal(t, InfoLevel, GlobalLevel())
+
+	// test restoring more than once has no effect
+	SetGlobalLevel(WarnLevel)
+	restore()
+	assert.Equal(t, WarnLevel, GlobalLevel())
+
+	// test overlapping scopes restored out of order
+	restore1 := TemporaryLevel(DebugLevel)
+	restore2 := TemporaryLevel(TraceLevel)
+	restore1()
+	assert.Equal(t, TraceLevel, GlobalLevel())
+	restore3 := TemporaryLevel(ErrorLevel)
+	restore3()
+	assert.Equal(t, TraceLevel, GlobalLevel())
+	restore2()
+	assert.Equal(t, WarnLevel, GlobalLevel())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
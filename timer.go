@@ -0,0 +1,84 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"sync"
+	"time"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Constants
+//======================================================================================================================
+
+// durationFieldName defines the name of the field containing the duration measured by a Timer.
+const durationFieldName = "duration"
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _timerClock defines the clock used by timers, used for testing.
+var _timerClock = time.Now
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// Timer measures the duration of an operation, see StartTimer.
+type Timer struct {
+	name  string
+	start time.Time
+	once  sync.Once
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// StartTimer starts measuring the duration of an operation, such as a database query. Call Stop or StopAt on the
+// returned Timer to log the name of the operation with the measured duration, for example using
+// defer log.StartTimer("db query").Stop().
+func StartTimer(name string) *Timer {
+	return &Timer{name: name, start: _timerClock()}
+}
+
+// Stop logs the name of the operation with the duration since the start of the Timer as debug message, using a
+// "duration" field. The duration is rendered in milliseconds. Only the first call to Stop or StopAt logs a message.
+func (t *Timer) Stop() {
+	t.StopAt(DebugLevel)
+}
+
+// StopAt logs the name of the operation with the duration since the start of the Timer at the given level, similar to
+// Stop. Only the first call to Stop or StopAt logs a message.
+func (t *Timer) StopAt(level Level) {
+	t.once.Do(func() {
+		d := _timerClock().Sub(t.start)
+		_logger.log(level, []Field{{Key: durationFieldName, Value: d}}, t.name, nil)
+	})
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,52 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+func TestTimer(t *testing.T) {
+	w := newTestLogger(t, JSON)
+	SetTimestamp(false)
+	defer SetTimestamp(true)
+	defer func(clock func() time.Time) { _timerClock = clock }(_timerClock)
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	_timerClock = func() time.Time { return now }
+
+	// test the duration is logged at debug level once
+	timer := StartTimer("db query")
+	now = now.Add(1500 * time.Millisecond)
+	timer.Stop()
+	timer.Stop()
+	timer.StopAt(ErrorLevel)
+	assert.Equal(t, Buffer{`{"level":"debug","duration":1500,"message":"db query"}`}, w.Buffer())
+
+	// test the level can be chosen
+	w.Reset()
+	SetFormatting(Default, true)
+	timer = StartTimer("db query")
+	now = now.Add(250 * time.Millisecond)
+	timer.StopAt(WarnLevel)
+	assert.Equal(t, Buffer{"WARN   db query duration=250"}, w.Buffer())
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
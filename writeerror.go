@@ -0,0 +1,129 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Variables
+//======================================================================================================================
+
+// _writeErrorHandler defines the callback invoked when a writer fails, see SetWriteErrorHandler.
+var _writeErrorHandler ErrorHandler = defaultWriteErrorHandler
+
+// _writeErrorMu protects the write error handler.
+var _writeErrorMu sync.RWMutex
+
+// _writeErrorWarning ensures the default warning about a failing writer is shown only once.
+var _writeErrorWarning sync.Once
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Types
+//======================================================================================================================
+
+// ErrorHandler defines a callback that is invoked when a writer fails to write a log, see SetWriteErrorHandler.
+type ErrorHandler func(error)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Types
+//======================================================================================================================
+
+// errorWriter implements a log writer that reports the errors of the wrapped writer to the write error handler. It is
+// used internally by the zerolog handler of a Logger, as zerolog does not report write errors back to the caller.
+type errorWriter struct {
+	writer io.Writer
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Private Functions
+//======================================================================================================================
+
+// defaultWriteErrorHandler writes a warning to stderr for the first failing write. Subsequent errors are ignored to
+// avoid flooding stderr, for example when a disk is full.
+func defaultWriteErrorHandler(err error) {
+	_writeErrorWarning.Do(func() {
+		fmt.Fprintf(os.Stderr, "Cannot write log: %s\n", err.Error())
+	})
+}
+
+// handleWriteError invokes the write error handler if err is not nil.
+func handleWriteError(err error) {
+	if err == nil {
+		return
+	}
+	_writeErrorMu.RLock()
+	h := _writeErrorHandler
+	_writeErrorMu.RUnlock()
+	h(err)
+}
+
+// Write implements the io.Writer interface for errorWriter. Errors are reported to the write error handler instead of
+// the caller, so the remaining writers of a Logger still receive the log.
+func (w *errorWriter) Write(p []byte) (n int, err error) {
+	_, err = w.writer.Write(p)
+	handleWriteError(err)
+	return len(p), nil
+}
+
+// WriteLevel implements the zerolog.LevelWriter interface for errorWriter. It passes the level to the wrapped writer
+// if supported.
+func (w *errorWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	if lw, ok := w.writer.(zerolog.LevelWriter); ok {
+		_, err = lw.WriteLevel(level, p)
+		handleWriteError(err)
+		return len(p), nil
+	}
+	return w.Write(p)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Public Functions
+//======================================================================================================================
+
+// SetWriteErrorHandler sets the callback that is invoked when a writer returns an error, for example when the disk of a
+// file writer is full. By default, a single warning is written to stderr for the first error. The handler must not log
+// using this package, as this may fail again. A nil handler restores the default behavior.
+func SetWriteErrorHandler(h ErrorHandler) {
+	if h == nil {
+		h = defaultWriteErrorHandler
+	}
+	_writeErrorMu.Lock()
+	defer _writeErrorMu.Unlock()
+	_writeErrorHandler = h
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
@@ -0,0 +1,58 @@
+// Copyright © 2021 Mark Dumay. All rights reserved.
+// Use of this source code is governed by The MIT License (MIT) that can be found in the LICENSE file.
+
+package log
+
+//======================================================================================================================
+// region Import Statements
+//======================================================================================================================
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================
+
+//======================================================================================================================
+// region Test Functions
+//======================================================================================================================
+
+// failWriter defines a writer that always fails, used to simulate a full disk.
+type failWriter struct{}
+
+func (w *failWriter) SetFormatting(format Format, noColor bool) {}
+
+func (w *failWriter) Write(p []byte) (n int, err error) {
+	return 0, errors.New("disk full")
+}
+
+func TestSetWriteErrorHandler(t *testing.T) {
+	var errs []error
+	SetWriteErrorHandler(func(err error) { errs = append(errs, err) })
+	defer SetWriteErrorHandler(nil)
+
+	// test the handler fires for a failing writer, while the other writers still receive the log
+	b := NewBufferedWriter(Default, true)
+	l := NewLogger(Default, true, &failWriter{}, b)
+	l.Info("first message")
+	l.Warn("second message")
+	assert.Equal(t, []error{errors.New("disk full"), errors.New("disk full")}, errs)
+	assert.Equal(t, Buffer{"first message", "WARN   second message"}, b.Buffer())
+
+	// test the handler fires for a single writer
+	errs = nil
+	l = NewLoggerWithLevel(JSON, InfoLevel, true, &failWriter{})
+	l.Error("third message")
+	_, err := l.Write([]byte("fourth message"))
+	assert.NoError(t, err)
+	assert.Len(t, errs, 2)
+}
+
+//======================================================================================================================
+// endregion
+//======================================================================================================================